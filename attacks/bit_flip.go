@@ -0,0 +1,120 @@
+//This file contains a bit-flipping forger for CBC and CTR ciphertexts
+
+package attacks
+
+import (
+	"errors"
+	"fmt"
+)
+
+//BitFlipMode is the cipher mode of a ciphertext given to BitFlip
+type BitFlipMode int
+
+//Modes for BitFlip. In CTR mode flipping a bit of the ciphertext flips
+//the same bit of the plaintext. In CBC mode it flips the same bit of
+//the next plaintext block and scrambles the block it is in; flipping
+//bits of the IV alters the first block without scrambling anything.
+const (
+	FlipCTR BitFlipMode = iota
+	FlipCBC
+)
+
+//FlipEdit asks BitFlip to make the plaintext at Offset read Text
+type FlipEdit struct {
+	Offset int
+	Text   []byte
+}
+
+//BitFlip describes a ciphertext to alter by flipping bits. Only the
+//plaintext bytes under the edits need be known; Known says which bytes
+//of Ptext are, and is nil if all of them are.
+type BitFlip struct {
+	Mode      BitFlipMode
+	Ctext     []byte
+	Ptext     []byte
+	Known     []bool
+	BlockSize int    //CBC only
+	IV        []byte //CBC only; nil if the IV cannot be altered
+}
+
+//FlipResult is an altered ciphertext returned by BitFlip.Apply
+type FlipResult struct {
+	Ctext     []byte
+	IV        []byte //CBC only; the IV, altered if the first block was edited
+	Scrambled []int  //indices of the plaintext blocks which decrypt to garbage
+}
+
+//Apply returns the ciphertext altered so that each edit's bytes
+//decrypt to its Text. Returns a non-nil error if Known is not as long
+//as the plaintext, an edit runs past the end of the plaintext, covers
+//an unknown plaintext byte or one another edit covers, needs the IV
+//when none was given, or lies in a block another edit scrambles.
+func (f BitFlip) Apply(edits ...FlipEdit) (FlipResult, error) {
+	if len(f.Ptext) > len(f.Ctext) {
+		return FlipResult{}, errors.New("Plaintext is longer than the ciphertext")
+	}
+	if f.Known != nil && len(f.Known) != len(f.Ptext) {
+		return FlipResult{}, errors.New("Known must be as long as the plaintext")
+	}
+	if f.Mode == FlipCBC && f.BlockSize <= 0 {
+		return FlipResult{}, errors.New("CBC needs a block size")
+	}
+	if f.Mode == FlipCBC && f.IV != nil && len(f.IV) != f.BlockSize {
+		return FlipResult{}, errors.New("IV must be one block long")
+	}
+	res := FlipResult{
+		Ctext: append([]byte{}, f.Ctext...),
+		IV:    append([]byte{}, f.IV...),
+	}
+	if f.IV == nil {
+		res.IV = nil
+	}
+
+	edited := make(map[int]bool)
+	flipped := make(map[int]bool)
+	for _, e := range edits {
+		if e.Offset < 0 || e.Offset+len(e.Text) > len(f.Ptext) {
+			return FlipResult{}, fmt.Errorf("Edit at %v runs past the end of the plaintext", e.Offset)
+		}
+		for i, b := range e.Text {
+			p := e.Offset + i
+			if f.Known != nil && !f.Known[p] {
+				return FlipResult{}, fmt.Errorf("Plaintext byte %v is not known", p)
+			}
+			if flipped[p] {
+				return FlipResult{}, fmt.Errorf("Plaintext byte %v is edited twice", p)
+			}
+			flipped[p] = true
+			diff := f.Ptext[p] ^ b
+			switch f.Mode {
+			case FlipCTR:
+				res.Ctext[p] ^= diff
+			case FlipCBC:
+				block := p / f.BlockSize
+				edited[block] = true
+				if block == 0 {
+					if res.IV == nil {
+						return FlipResult{}, errors.New("Editing the first block needs the IV")
+					}
+					res.IV[p] ^= diff
+				} else {
+					res.Ctext[p-f.BlockSize] ^= diff
+				}
+			}
+		}
+	}
+
+	if f.Mode == FlipCBC {
+		numBlocks := (len(f.Ptext) + f.BlockSize - 1) / f.BlockSize
+		for block := 1; block < numBlocks; block++ {
+			if !edited[block] {
+				continue
+			}
+			if edited[block-1] {
+				return FlipResult{}, fmt.Errorf("Editing block %v scrambles edited block %v", block, block-1)
+			}
+			res.Scrambled = append(res.Scrambled, block-1)
+		}
+	}
+	return res, nil
+}
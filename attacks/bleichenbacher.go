@@ -1,22 +1,27 @@
-package main
+package attacks
 
 import (
 	"fmt"
 	"math/big"
+
+	"github.com/alanese/cryptopals/mathutil"
+	"github.com/alanese/cryptopals/oracles"
 )
 
 //C47_2a implements step 2a of Bleichenbacher's attack
-func C47_2a(c0, B, e, d, n *big.Int) *big.Int {
+func C47_2a(c0, B, e, n *big.Int, oracle oracles.RSAPaddingOracle, verbose bool) *big.Int {
 	lowerBdDenom := big.NewInt(0).Mul(B, big.NewInt(3))
 	lowerBd := big.NewRat(0, 1).SetFrac(n, lowerBdDenom)
 
-	s1 := RatCeil(lowerBd)
+	s1 := mathutil.RatCeil(lowerBd)
 	for {
-		fmt.Printf("Testing s1 = %X\n", s1)
+		if verbose {
+			fmt.Printf("Testing s1 = %X\n", s1)
+		}
 		ctext := big.NewInt(0).Exp(s1, e, n)
 		ctext.Mul(c0, ctext)
 		ctext.Mod(ctext, n)
-		if C47PaddingOracle(ctext.Bytes(), d, n) {
+		if oracle.PKCS1Conformant(ctext.Bytes()) {
 			return s1
 		}
 		s1.Add(s1, big.NewInt(1))
@@ -24,13 +29,13 @@ func C47_2a(c0, B, e, d, n *big.Int) *big.Int {
 }
 
 //C47_2b implements step 2b of Bleichenbacher's attack
-func C47_2b(c0, prevS, e, d, n *big.Int) *big.Int {
+func C47_2b(c0, prevS, e, n *big.Int, oracle oracles.RSAPaddingOracle) *big.Int {
 	si := big.NewInt(0).Add(prevS, big.NewInt(1))
 	for {
 		ctext := big.NewInt(0).Exp(si, e, n)
 		ctext.Mul(c0, ctext)
 		ctext.Mod(ctext, n)
-		if C47PaddingOracle(ctext.Bytes(), d, n) {
+		if oracle.PKCS1Conformant(ctext.Bytes()) {
 			return si
 		}
 
@@ -39,7 +44,7 @@ func C47_2b(c0, prevS, e, d, n *big.Int) *big.Int {
 }
 
 //C47_2c implements step 2c of Bleichenbacher's attack
-func C47_2c(c0, prevS, B, e, d, n *big.Int, a, b *big.Rat) (*big.Int, *big.Int) {
+func C47_2c(c0, prevS, B, e, n *big.Int, a, b *big.Rat, oracle oracles.RSAPaddingOracle) (*big.Int, *big.Int) {
 	one := big.NewInt(1)
 	prevSRat := big.NewRat(0, 1).SetInt(prevS)
 	BRat := big.NewRat(0, 1).SetInt(B)
@@ -52,25 +57,25 @@ func C47_2c(c0, prevS, B, e, d, n *big.Int, a, b *big.Rat) (*big.Int, *big.Int)
 	threeB := big.NewRat(0, 1).Mul(big.NewRat(3, 1), BRat)
 
 	twoNInv := big.NewRat(0, 1).SetFrac(big.NewInt(2), n)
-	ri := RatCeil(big.NewRat(0, 1).Mul(twoNInv, numer))
+	ri := mathutil.RatCeil(big.NewRat(0, 1).Mul(twoNInv, numer))
 
 	for {
 		tmp0 := big.NewInt(0).Mul(ri, n)
 		tmp0Rat := big.NewRat(0, 1).SetInt(tmp0)
 		tmp0Rat.Add(twoB, tmp0Rat)
-		sLowerBd := RatCeil(big.NewRat(0, 1).Quo(tmp0Rat, b))
+		sLowerBd := mathutil.RatCeil(big.NewRat(0, 1).Quo(tmp0Rat, b))
 
 		tmp1 := big.NewInt(0).Mul(ri, n)
 		tmp1Rat := big.NewRat(0, 1).SetInt(tmp1)
 		tmp1Rat.Add(threeB, tmp1Rat)
-		sUpperBd := RatCeil(big.NewRat(0, 1).Quo(tmp1Rat, a))
+		sUpperBd := mathutil.RatCeil(big.NewRat(0, 1).Quo(tmp1Rat, a))
 
 		si := big.NewInt(0).Set(sLowerBd)
 		for si.Cmp(sUpperBd) < 0 {
 			ctext := big.NewInt(0).Exp(si, e, n)
 			ctext.Mul(ctext, c0)
 			ctext.Mod(ctext, n)
-			if C47PaddingOracle(ctext.Bytes(), d, n) {
+			if oracle.PKCS1Conformant(ctext.Bytes()) {
 				return nil, si
 			}
 			si.Add(si, one)
@@ -80,7 +85,7 @@ func C47_2c(c0, prevS, B, e, d, n *big.Int, a, b *big.Rat) (*big.Int, *big.Int)
 }
 
 //C47GetStep3Interval implements a portion of step 3 of Bleichenbacher's attack
-func C47GetStep3Interval(a, b *big.Rat, B, r, n, si *big.Int) Interval {
+func C47GetStep3Interval(a, b *big.Rat, B, r, n, si *big.Int) mathutil.Interval {
 	rn := big.NewInt(0).Mul(r, n)
 	twoB := big.NewInt(0).Mul(B, big.NewInt(2))
 	threeBMinusOne := big.NewInt(0).Mul(B, big.NewInt(3))
@@ -92,14 +97,14 @@ func C47GetStep3Interval(a, b *big.Rat, B, r, n, si *big.Int) Interval {
 	lowerRat := big.NewRat(0, 1).SetFrac(lowerNumer, si)
 	upperRat := big.NewRat(0, 1).SetFrac(upperNumer, si)
 
-	lowerBd := RatMax(a, big.NewRat(0, 1).SetInt(RatCeil(lowerRat)))
-	upperBd := RatMin(b, big.NewRat(0, 1).SetInt(RatFloor(upperRat)))
+	lowerBd := mathutil.RatMax(a, big.NewRat(0, 1).SetInt(mathutil.RatCeil(lowerRat)))
+	upperBd := mathutil.RatMin(b, big.NewRat(0, 1).SetInt(mathutil.RatFloor(upperRat)))
 
-	return Interval{lowerBd, upperBd}
+	return mathutil.Interval{Min: lowerBd, Max: upperBd}
 }
 
 //C47_3 implements step 3 of Bleichenbacher's attack
-func C47_3(prevM []Interval, B, si, n *big.Int) []Interval {
+func C47_3(prevM []mathutil.Interval, B, si, n *big.Int, verbose bool) []mathutil.Interval {
 	one := big.NewInt(1)
 	siRat := big.NewRat(0, 1).SetInt(si)
 	oneOverN := big.NewRat(0, 1).SetFrac(one, n)
@@ -108,7 +113,7 @@ func C47_3(prevM []Interval, B, si, n *big.Int) []Interval {
 	twoBRat := big.NewRat(0, 1).SetInt(twoB)
 	threeBMinusOne := big.NewInt(0).Sub(threeB, one)
 	threeBMinusOneRat := big.NewRat(0, 1).SetInt(threeBMinusOne)
-	newM := make([]Interval, 0)
+	newM := make([]mathutil.Interval, 0)
 	for _, intvl := range prevM {
 		a := intvl.Min
 		b := intvl.Max
@@ -121,10 +126,12 @@ func C47_3(prevM []Interval, B, si, n *big.Int) []Interval {
 		upperBd.Sub(upperBd, twoBRat)
 		upperBd.Mul(upperBd, oneOverN)
 
-		lowerBdInt := RatCeil(lowerBd)
-		upperBdInt := RatFloor(upperBd)
+		lowerBdInt := mathutil.RatCeil(lowerBd)
+		upperBdInt := mathutil.RatFloor(upperBd)
 
-		fmt.Printf("Lower %X\nUpper %X\n", lowerBdInt, upperBdInt)
+		if verbose {
+			fmt.Printf("Lower %X\nUpper %X\n", lowerBdInt, upperBdInt)
+		}
 
 		r := lowerBdInt
 		for r.Cmp(upperBdInt) <= 0 {
@@ -133,15 +140,18 @@ func C47_3(prevM []Interval, B, si, n *big.Int) []Interval {
 		}
 
 	}
-	fmt.Printf("Pre-simplification %v\n", newM)
-	return SimplifyIntervalUnion(newM)
+	if verbose {
+		fmt.Printf("Pre-simplification %v\n", newM)
+	}
+	return mathutil.SimplifyIntervalUnion(newM)
 }
 
 //BleichenbacherAttack decrypts msg, given the intended recipient's
-//public RSA keypair [e,n]. d is passed only for passing on to the padding
-//oracle. The attack assumes the original plaintext was properly padded
-//per PKCS#1v1.5
-func BleichenbacherAttack(msg []byte, e, d, n *big.Int) []byte {
+//public RSA keypair [e,n] and a padding oracle. The attack assumes
+//the original plaintext was properly padded per PKCS#1v1.5, and that
+//msg is left-padded to the length of n. The verbose parameter determines
+//whether status messages are printed to console.
+func BleichenbacherAttack(msg []byte, e, n *big.Int, oracle oracles.RSAPaddingOracle, verbose bool) []byte {
 	msgNum := big.NewInt(0).SetBytes(msg)
 	zero := big.NewInt(0)
 	one := big.NewInt(1)
@@ -161,45 +171,45 @@ func BleichenbacherAttack(msg []byte, e, d, n *big.Int) []byte {
 	initMin := big.NewRat(0, 1).SetFrac(initMinInt, big.NewInt(1))
 	initMax := big.NewRat(0, 1).SetFrac(initMaxInt, big.NewInt(1))
 
-	initInterval := Interval{initMin, initMax}
+	initInterval := mathutil.Interval{Min: initMin, Max: initMax}
 
 	//Set up initial values
-	initM := []Interval{initInterval}
+	initM := []mathutil.Interval{initInterval}
 	initS := big.NewInt(1)
 	initC := big.NewInt(0).Exp(initS, e, n)
 	initC.Mul(initC, msgNum)
 	initC.Mod(initC, n)
 
-	si := C47_2a(initC, B, e, d, n)
-	fmt.Println("Init s computed")
-	Mi := C47_3(initM, B, si, n)
-	fmt.Println("Init M computed")
+	si := C47_2a(initC, B, e, n, oracle, verbose)
+	if verbose {
+		fmt.Println("Init s computed")
+	}
+	Mi := C47_3(initM, B, si, n, verbose)
+	if verbose {
+		fmt.Println("Init M computed")
+	}
 
 	newS := big.NewInt(0).Set(si)
 	i := 2
 	for {
-		fmt.Printf("Beginning iteration i=%v\n", i)
-		fmt.Println(Mi)
+		if verbose {
+			fmt.Printf("Beginning iteration i=%v\n", i)
+			fmt.Println(Mi)
+		}
 		if len(Mi) == 1 && Mi[0].Length().Cmp(one) == 0 {
-			return RatCeil(Mi[0].Max).Bytes()
+			return mathutil.RatCeil(Mi[0].Max).Bytes()
 		}
 		si = newS
 		if len(Mi) > 1 {
-			newS = C47_2b(initC, si, e, d, n)
+			newS = C47_2b(initC, si, e, n, oracle)
 		} else {
-			_, newS = C47_2c(initC, si, B, e, d, n, Mi[0].Min, Mi[0].Max)
+			_, newS = C47_2c(initC, si, B, e, n, Mi[0].Min, Mi[0].Max, oracle)
+		}
+		Mi = C47_3(Mi, B, newS, n, verbose)
+		if verbose {
+			fmt.Printf("Mi = %v\n", Mi)
 		}
-		Mi = C47_3(Mi, B, newS, n)
-		fmt.Printf("Mi = %v\n", Mi)
 		i++
 	}
 
 }
-
-//C47PaddingOracle decrypts m with the RSA private keypair [d, n]
-//and checks whether the plaintext is properly paddid according to
-//PKCS#1v1.5
-func C47PaddingOracle(m []byte, d, n *big.Int) bool {
-	pText := RSADecryptPad(m, d, n)
-	return RSAPKCS1Validate(pText)
-}
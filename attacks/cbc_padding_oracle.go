@@ -0,0 +1,52 @@
+//This file contains a general CBC padding oracle attack,
+//built on the byte-at-a-time attack from challenge 17
+
+package attacks
+
+import (
+	"errors"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//CBCPaddingOracleDecrypt decrypts an entire CBC ciphertext, including
+//the first block, using a padding oracle which accepts a chosen IV.
+//The block size is taken to be the length of iv. The returned plaintext
+//still carries its padding. Returns a non-nil error if the ciphertext
+//is not a whole number of blocks.
+func CBCPaddingOracleDecrypt(ctext, iv []byte, oracle oracles.CBCPaddingOracle) ([]byte, error) {
+	blockSize := len(iv)
+	if blockSize == 0 || len(ctext)%blockSize != 0 {
+		return nil, errors.New("Ciphertext length not a multiple of block size")
+	}
+	ptext := make([]byte, 0, len(ctext))
+	prevBlock := iv
+	for _, block := range bytesutil.Chunkify(ctext, blockSize) {
+		ptext = append(ptext, Challenge17GetLastBlock(block, prevBlock, oracle)...)
+		prevBlock = block
+	}
+	return ptext, nil
+}
+
+//CBCPaddingOracleEncrypt forges an IV and ciphertext which decrypt
+//to ptext (after PKCS#7 padding) under the oracle's secret key, by
+//running the padding oracle attack in reverse (CBC-R). Each block
+//is decrypted under a zero IV to find the intermediate state, which
+//is then xored with the desired plaintext to give the previous block.
+func CBCPaddingOracleEncrypt(ptext []byte, blockSize int, oracle oracles.CBCPaddingOracle) (ctext, iv []byte) {
+	padded := ciphers.PKCSPad(append([]byte{}, ptext...), blockSize)
+	blocks := bytesutil.Chunkify(padded, blockSize)
+	zeroes := make([]byte, blockSize)
+
+	nextBlock := bytesutil.GenerateRandomByteSlice(blockSize)
+	forged := nextBlock
+	for i := len(blocks) - 1; i >= 0; i-- {
+		intermediate := Challenge17GetLastBlock(nextBlock, zeroes, oracle)
+		prevBlock, _ := bytesutil.XorBufs(intermediate, blocks[i])
+		forged = append(prevBlock, forged...)
+		nextBlock = prevBlock
+	}
+	return forged[blockSize:], forged[:blockSize]
+}
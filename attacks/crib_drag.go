@@ -0,0 +1,134 @@
+//This file contains a crib-dragging engine for two messages
+//encrypted under the same stream cipher keystream
+
+package attacks
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/alanese/cryptopals/scoring"
+)
+
+//CribMatch is one placement of a crib by CribDragSession.Drag
+type CribMatch struct {
+	Offset   int
+	Fragment []byte //the other message's plaintext at Offset if the crib is right
+	Score    float64
+}
+
+//CribDragSession attacks two ciphertexts encrypted under the same
+//keystream, such as CTR with a repeated nonce, EncryptMT19937Stream
+//with a repeated seed or RC4 with a repeated key. XORing the
+//ciphertexts cancels the keystream, so a guessed word in one message
+//reveals the other message at the same position. The session keeps
+//track of what is known of the keystream and both plaintexts.
+type CribDragSession struct {
+	ctexts    [2][]byte
+	keystream []byte
+	known     []bool
+	scorer    scoring.Scorer
+	history   []cribDragSnapshot
+}
+
+//cribDragSnapshot is a saved keystream state for Undo
+type cribDragSnapshot struct {
+	keystream []byte
+	known     []bool
+}
+
+//NewCribDragSession starts a session on two ciphertexts, ranking
+//crib placements with the given scorer
+func NewCribDragSession(c1, c2 []byte, scorer scoring.Scorer) *CribDragSession {
+	n := len(c1)
+	if len(c2) > n {
+		n = len(c2)
+	}
+	return &CribDragSession{
+		ctexts:    [2][]byte{c1, c2},
+		keystream: make([]byte, n),
+		known:     make([]bool, n),
+		scorer:    scorer,
+	}
+}
+
+//overlap returns the length of the shorter ciphertext
+func (s *CribDragSession) overlap() int {
+	if len(s.ctexts[0]) < len(s.ctexts[1]) {
+		return len(s.ctexts[0])
+	}
+	return len(s.ctexts[1])
+}
+
+//Drag slides crib across every position at which both messages are
+//present and returns the resulting fragments of the other message,
+//best-scoring first. The same placements hold whichever message the
+//crib is in, since the fragment is the crib XORed with both ciphertexts.
+func (s *CribDragSession) Drag(crib []byte) []CribMatch {
+	matches := []CribMatch{}
+	for off := 0; off+len(crib) <= s.overlap(); off++ {
+		frag := make([]byte, len(crib))
+		for i, b := range crib {
+			frag[i] = b ^ s.ctexts[0][off+i] ^ s.ctexts[1][off+i]
+		}
+		matches = append(matches, CribMatch{off, frag, s.scorer.Score(frag)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score < matches[j].Score })
+	return matches
+}
+
+//Place records that message msg (0 or 1) has plaintext text at the
+//given offset, which fixes the keystream there and so the other
+//message too. Earlier placements over the same bytes are overwritten.
+//Returns a non-nil error if the text runs past the end of the message.
+func (s *CribDragSession) Place(msg, offset int, text []byte) error {
+	if msg != 0 && msg != 1 {
+		return errors.New("Message must be 0 or 1")
+	}
+	c := s.ctexts[msg]
+	if offset < 0 || offset+len(text) > len(c) {
+		return errors.New("Text runs past the end of the message")
+	}
+	s.history = append(s.history, cribDragSnapshot{
+		append([]byte{}, s.keystream...),
+		append([]bool{}, s.known...),
+	})
+	for i, b := range text {
+		s.keystream[offset+i] = c[offset+i] ^ b
+		s.known[offset+i] = true
+	}
+	return nil
+}
+
+//Undo reverts the most recent Place, reporting whether there
+//was one to revert
+func (s *CribDragSession) Undo() bool {
+	if len(s.history) == 0 {
+		return false
+	}
+	last := s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+	s.keystream, s.known = last.keystream, last.known
+	return true
+}
+
+//Keystream returns the keystream recovered so far, along with which
+//of its bytes are known
+func (s *CribDragSession) Keystream() ([]byte, []bool) {
+	return append([]byte{}, s.keystream...), append([]bool{}, s.known...)
+}
+
+//Plaintext returns message msg (0 or 1) as recovered so far, with
+//unknown bytes replaced by unknown
+func (s *CribDragSession) Plaintext(msg int, unknown byte) []byte {
+	c := s.ctexts[msg]
+	p := make([]byte, len(c))
+	for i := range p {
+		if s.known[i] {
+			p[i] = c[i] ^ s.keystream[i]
+		} else {
+			p[i] = unknown
+		}
+	}
+	return p
+}
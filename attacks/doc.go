@@ -0,0 +1,3 @@
+//Package attacks contains the attacks from the Cryptopals
+//challenges
+package attacks
@@ -0,0 +1,209 @@
+//This file contains a generic byte-at-a-time attack on ECB
+//oracles which append a secret to attacker-controlled input
+
+package attacks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//ECBSuffixResult is what BreakECBSuffix learned about an oracle
+type ECBSuffixResult struct {
+	BlockSize int
+	PrefixMin int //shortest prefix seen in front of the input
+	PrefixMax int //longest prefix seen; equal to PrefixMin if fixed
+	Secret    []byte
+}
+
+//maxECBBlockSize is the largest block size BreakECBSuffix looks for
+const maxECBBlockSize = 64
+
+//the bytes of the two marker blocks BreakECBSuffix puts
+//in front of its input to find where that input starts
+const (
+	ecbMarker1 = 0x00
+	ecbMarker2 = 0xff
+)
+
+//ecbBlockSize finds the block size of an oracle from the greatest
+//common divisor of its output lengths over a range of input lengths.
+//This holds even if a random-length prefix is added to each input.
+func ecbBlockSize(oracle oracles.ECBOracle) (int, error) {
+	g := 0
+	for i := 0; i <= 2*maxECBBlockSize; i++ {
+		n := len(oracle.Encrypt(bytes.Repeat([]byte("A"), i)))
+		for n != 0 {
+			g, n = n, g%n
+		}
+	}
+	if g == 0 || g > maxECBBlockSize {
+		return 0, errors.New("Could not find a block size")
+	}
+	return g, nil
+}
+
+//ecbRepeatedBlock encrypts 3 blocks' worth of b, and returns the
+//encryption of a single block of b, found as the first pair of equal
+//consecutive blocks. Returns a non-nil error if there is no such pair,
+//which means the oracle is not using ECB.
+func ecbRepeatedBlock(oracle oracles.ECBOracle, b byte, blockSize int) ([]byte, error) {
+	ctext := oracle.Encrypt(bytes.Repeat([]byte{b}, 3*blockSize))
+	if !DetectECB(ctext, blockSize) {
+		return nil, errors.New("Oracle does not appear to use ECB")
+	}
+	for i := blockSize; i+blockSize <= len(ctext); i += blockSize {
+		if bytes.Equal(ctext[i-blockSize:i], ctext[i:i+blockSize]) {
+			return ctext[i : i+blockSize], nil
+		}
+	}
+	return nil, errors.New("Oracle does not appear to use ECB")
+}
+
+//alignedECBOracle wraps an ECB oracle which may put a prefix, of
+//fixed or random length, in front of its input. It puts a marker
+//block in front of each input and repeats the query until the input
+//starts on a block boundary, then strips everything up to the input.
+type alignedECBOracle struct {
+	oracle    oracles.ECBOracle
+	blockSize int
+	marker1   []byte //encryption of a block of ecbMarker1
+	marker2   []byte //encryption of a block of ecbMarker2
+	pad       int    //marker1 bytes in front of marker 1 which last worked
+	prefixMin int
+	prefixMax int
+	queries   int
+}
+
+//encrypt returns the encryption of ptext and whatever follows it,
+//starting on a block boundary. Returns a non-nil error if the input
+//could not be aligned.
+func (a *alignedECBOracle) encrypt(ptext []byte) ([]byte, error) {
+	bs := a.blockSize
+	for attempt := 0; attempt < 32*bs; attempt++ {
+		pad := (a.pad + attempt) % bs
+		input := bytes.Repeat([]byte{ecbMarker1}, bs+pad)
+		input = append(input, bytes.Repeat([]byte{ecbMarker2}, bs)...)
+		input = append(input, ptext...)
+		ctext := a.oracle.Encrypt(input)
+		a.queries++
+		//a block of marker 2 right after a block of marker 1 can only
+		//be our own marker 2, starting on a block boundary
+		for i := bs; i+bs <= len(ctext); i += bs {
+			if bytes.Equal(ctext[i:i+bs], a.marker2) && bytes.Equal(ctext[i-bs:i], a.marker1) {
+				a.pad = pad
+				prefix := i - bs - pad
+				if prefix < a.prefixMin {
+					a.prefixMin = prefix
+				}
+				if prefix > a.prefixMax {
+					a.prefixMax = prefix
+				}
+				return ctext[i+bs:], nil
+			}
+		}
+	}
+	return nil, errors.New("Could not align input to a block boundary")
+}
+
+//BreakECBSuffix recovers the secret appended to attacker-controlled
+//input by an ECB oracle, for any block cipher with blocks of up to
+//64 bytes padded with PKCS#7. The oracle may also put a prefix in
+//front of the input, whose length may change on every call. The block
+//size and the range of prefix lengths seen are returned along with the
+//secret. Wrap a plain function in oracles.ECBOracleFunc. If verbose is
+//true, prints the secret as it is decrypted.
+func BreakECBSuffix(oracle oracles.ECBOracle, verbose bool) (ECBSuffixResult, error) {
+	bs, err := ecbBlockSize(oracle)
+	if err != nil {
+		return ECBSuffixResult{}, err
+	}
+	marker1, err := ecbRepeatedBlock(oracle, ecbMarker1, bs)
+	if err != nil {
+		return ECBSuffixResult{}, err
+	}
+	marker2, err := ecbRepeatedBlock(oracle, ecbMarker2, bs)
+	if err != nil {
+		return ECBSuffixResult{}, err
+	}
+	if verbose {
+		fmt.Printf("Block size %v, ECB confirmed\n", bs)
+	}
+	a := &alignedECBOracle{
+		oracle:    oracle,
+		blockSize: bs,
+		marker1:   marker1,
+		marker2:   marker2,
+		prefixMin: int(^uint(0) >> 1),
+		prefixMax: -1,
+	}
+
+	//PKCS#7 adds a whole block when the input and secret fill the last
+	//block exactly, so the secret's length shows where the output grows
+	base, err := a.encrypt(nil)
+	if err != nil {
+		return ECBSuffixResult{}, err
+	}
+	secretLen := -1
+	for i := 1; i <= bs; i++ {
+		ctext, err := a.encrypt(bytes.Repeat([]byte("A"), i))
+		if err != nil {
+			return ECBSuffixResult{}, err
+		}
+		if len(ctext) > len(base) {
+			secretLen = len(base) - i
+			break
+		}
+	}
+	if secretLen < 0 {
+		return ECBSuffixResult{}, errors.New("Could not find the length of the secret")
+	}
+	if verbose {
+		fmt.Printf("Secret is %v bytes\n", secretLen)
+	}
+
+	//known starts with a block less one of filler, so the last
+	//blockSize-1 bytes of known are always available as a dictionary head
+	known := bytes.Repeat([]byte("A"), bs-1)
+	for i := 0; i < secretLen; i++ {
+		block := i / bs
+		shift := bs - 1 - i%bs
+		target, err := a.encrypt(known[:shift])
+		if err != nil {
+			return ECBSuffixResult{}, err
+		}
+		targetBlock := target[block*bs : (block+1)*bs]
+		head := known[len(known)-(bs-1):]
+		found := false
+		for j := 0; j < 256; j++ {
+			test, err := a.encrypt(append(append([]byte{}, head...), byte(j)))
+			if err != nil {
+				return ECBSuffixResult{}, err
+			}
+			if bytes.Equal(test[:bs], targetBlock) {
+				known = append(known, byte(j))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ECBSuffixResult{}, fmt.Errorf("No match for secret byte %v", i)
+		}
+		if verbose {
+			fmt.Printf("%q\n", known[bs-1:])
+		}
+	}
+	if verbose {
+		fmt.Printf("%v oracle queries\n", a.queries)
+	}
+
+	return ECBSuffixResult{
+		BlockSize: bs,
+		PrefixMin: a.prefixMin,
+		PrefixMax: a.prefixMax,
+		Secret:    known[bs-1:],
+	}, nil
+}
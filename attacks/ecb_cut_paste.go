@@ -0,0 +1,184 @@
+//This file contains a cut-and-paste forger for structured
+//tokens encrypted with ECB
+
+package attacks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//ECBTokenLayout describes the plaintext an ECB oracle builds around
+//attacker-controlled input: Before, then the input after Escape, then
+//After. Bytes which vary between calls or are secret, such as a random
+//uid, are given as Unknown; only their number matters. Bytes equal to
+//Unknown cannot be forged.
+type ECBTokenLayout struct {
+	Before  string
+	After   string
+	Unknown byte
+	Escape  func(string) string //nil if the input is used as is
+}
+
+//escapes reports whether Escape would change s
+func (l ECBTokenLayout) escapes(s string) bool {
+	return l.Escape != nil && l.Escape(s) != s
+}
+
+//ECBCutPaste forges tokens encrypted with ECB under an unknown key by
+//encrypting each block of the forgery separately, with an input chosen
+//so that the block lines up with a block of the oracle's plaintext, and
+//pasting the ciphertext blocks together. A block can be forged if it
+//can be lined up so that its bytes from Before and After match the
+//layout and its bytes from the input survive escaping. If BlockSize is
+//0 it is found from the oracle.
+type ECBCutPaste struct {
+	Oracle    oracles.ECBOracle
+	Layout    ECBTokenLayout
+	BlockSize int
+}
+
+//blockSize returns f.BlockSize, finding it from the oracle if unset
+func (f *ECBCutPaste) blockSize() (int, error) {
+	if f.BlockSize == 0 {
+		bs, err := ecbBlockSize(f.Oracle)
+		if err != nil {
+			return 0, err
+		}
+		f.BlockSize = bs
+	}
+	return f.BlockSize, nil
+}
+
+//ecbFiller is the byte used for input not covered by the block being
+//forged, if the layout's escaping leaves it alone
+const ecbFiller = 'A'
+
+//PlanBlock finds an input for which block index of the oracle's
+//plaintext is block, trying the shortest inputs first. Returns a
+//non-nil error if there is no such input.
+func (f *ECBCutPaste) PlanBlock(block []byte) (input []byte, index int, err error) {
+	bs, err := f.blockSize()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(block) != bs {
+		return nil, 0, fmt.Errorf("Block must be %v bytes", bs)
+	}
+	l := f.Layout
+	filler := byte(ecbFiller)
+	if l.escapes(string(filler)) {
+		return nil, 0, errors.New("Filler byte does not survive escaping")
+	}
+	before, after := len(l.Before), len(l.After)
+	for n := 0; n <= before+after+2*bs; n++ {
+		padded := len(ciphers.PKCSPad(make([]byte, before+n+after), bs))
+	blocks:
+		for k := 0; k < padded/bs; k++ {
+			input = bytes.Repeat([]byte{filler}, n)
+			for i, b := range block {
+				p := k*bs + i
+				var want byte
+				switch {
+				case p < before:
+					want = l.Before[p]
+				case p < before+n:
+					if b == l.Unknown || l.escapes(string(b)) {
+						continue blocks
+					}
+					input[p-before] = b
+					continue
+				case p < before+n+after:
+					want = l.After[p-before-n]
+				default:
+					want = byte(padded - (before + n + after))
+				}
+				if b != want {
+					continue blocks
+				}
+			}
+			if !l.escapes(string(input)) {
+				return input, k, nil
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("Cannot forge block %q", block)
+}
+
+//EncryptBlock returns the encryption of a single block under the
+//oracle's key, found with PlanBlock. The query is repeated, up to 16
+//times, until the ciphertext is the length the layout predicts, in case
+//Unknown bytes of the layout vary in length.
+func (f *ECBCutPaste) EncryptBlock(block []byte) ([]byte, error) {
+	input, k, err := f.PlanBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	bs := f.BlockSize
+	l := f.Layout
+	want := len(ciphers.PKCSPad(make([]byte, len(l.Before)+len(input)+len(l.After)), bs))
+	for tries := 0; tries < 16; tries++ {
+		ctext := f.Oracle.Encrypt(input)
+		if len(ctext) == want {
+			return ctext[k*bs : (k+1)*bs], nil
+		}
+	}
+	return nil, errors.New("Ciphertext length does not match the layout")
+}
+
+//Forge returns an encryption of target, padded with PKCS#7, under the
+//oracle's key. Every block of the padded target must be forgeable;
+//a block containing Unknown bytes must line up with the same bytes
+//of the layout. Returns a non-nil error if any block cannot be forged.
+func (f *ECBCutPaste) Forge(target []byte) ([]byte, error) {
+	bs, err := f.blockSize()
+	if err != nil {
+		return nil, err
+	}
+	forged := []byte{}
+	cache := make(map[string][]byte)
+	for _, block := range bytesutil.Chunkify(ciphers.PKCSPad(target, bs), bs) {
+		c, ok := cache[string(block)]
+		if !ok {
+			c, err = f.EncryptBlock(block)
+			if err != nil {
+				return nil, err
+			}
+			cache[string(block)] = c
+		}
+		forged = append(forged, c...)
+	}
+	return forged, nil
+}
+
+//ReplaceAfter forges a token made from input, which must survive
+//escaping, up to the end of the last occurrence of cut in After, with
+//everything after that replaced by value. For example, with cut
+//"role=" and value "admin" it turns a user's token into an admin's,
+//and value can add further fields. The input is padded at the front
+//with filler so that cut ends on a block boundary.
+func (f *ECBCutPaste) ReplaceAfter(input, cut, value string) ([]byte, error) {
+	bs, err := f.blockSize()
+	if err != nil {
+		return nil, err
+	}
+	l := f.Layout
+	if l.escapes(input) {
+		return nil, errors.New("Input does not survive escaping")
+	}
+	end := strings.LastIndex(l.After, cut)
+	if end < 0 {
+		return nil, fmt.Errorf("%q does not occur in the layout", cut)
+	}
+	end += len(cut)
+	fill := (bs - (len(l.Before)+len(input)+end)%bs) % bs
+	input = strings.Repeat(string(ecbFiller), fill) + input
+	target := l.Before + input + l.After[:end] + value
+	return f.Forge([]byte(target))
+}
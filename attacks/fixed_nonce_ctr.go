@@ -0,0 +1,240 @@
+//This file contains a breaker for many ciphertexts encrypted
+//under the same keystream, such as CTR with a fixed nonce
+
+package attacks
+
+import (
+	"math"
+
+	"github.com/alanese/cryptopals/scoring"
+)
+
+//Crib is a known fragment of plaintext at a given offset of one
+//of the ciphertexts passed to BreakFixedNonceCTR
+type Crib struct {
+	Line   int
+	Offset int
+	Text   []byte
+}
+
+//dragWords returns the cribs dragged across sparsely-covered columns
+//of the keystream, where there is too little data to decide bytes one
+//at a time: the most common English words, bare and after a space
+func dragWords() []string {
+	words := []string{}
+	for _, w := range scoring.EnglishWords(1000) {
+		if len(w) > 1 {
+			words = append(words, w, " "+w)
+		}
+	}
+	return words
+}
+
+//sparseCoverage is the number of ciphertexts at or below which a
+//column is considered sparsely covered
+const sparseCoverage = 3
+
+//fixedNonceState holds the ciphertexts and current keystream guess
+//for BreakFixedNonceCTR
+type fixedNonceState struct {
+	ctexts    [][]byte
+	keystream []byte
+	fixed     []bool
+	scorer    scoring.Scorer
+}
+
+//coverage returns the number of ciphertexts at least i+1 bytes long
+func (st *fixedNonceState) coverage(i int) int {
+	n := 0
+	for _, c := range st.ctexts {
+		if len(c) > i {
+			n++
+		}
+	}
+	return n
+}
+
+//localScore scores the plaintexts of every ciphertext over columns
+//[from, to), plus two bytes of context either side. Scores are
+//weighted by length so windows of different sizes are comparable.
+func (st *fixedNonceState) localScore(from, to int) float64 {
+	total := 0.0
+	for _, c := range st.ctexts {
+		if len(c) <= from {
+			continue
+		}
+		lo := from - 2
+		if lo < 0 {
+			lo = 0
+		}
+		hi := to + 2
+		if hi > len(c) {
+			hi = len(c)
+		}
+		window := make([]byte, 0, hi-lo+1)
+		if lo == 0 {
+			//a line start follows a newline, which gives the
+			//scorer context for capitals
+			window = append(window, '\n')
+		}
+		for i := lo; i < hi; i++ {
+			window = append(window, c[i]^st.keystream[i])
+		}
+		total += st.scorer.Score(window) * float64(len(window))
+	}
+	return total
+}
+
+//column returns the bytes of every ciphertext in column i
+func (st *fixedNonceState) column(i int) []byte {
+	col := []byte{}
+	for _, c := range st.ctexts {
+		if len(c) > i {
+			col = append(col, c[i])
+		}
+	}
+	return col
+}
+
+//refine replaces each unfixed keystream byte in turn by the value
+//giving the best local score, until no single change helps
+func (st *fixedNonceState) refine() {
+	for pass := 0; pass < 4; pass++ {
+		changed := false
+		for i := range st.keystream {
+			if st.fixed[i] {
+				continue
+			}
+			orig := st.keystream[i]
+			best := orig
+			bestScore := st.localScore(i, i+1)
+			for v := 0; v < 256; v++ {
+				st.keystream[i] = byte(v)
+				if score := st.localScore(i, i+1); score < bestScore {
+					best, bestScore = byte(v), score
+				}
+			}
+			st.keystream[i] = best
+			changed = changed || best != orig
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+//drag slides common words across each ciphertext wherever every column
+//they would cover is sparsely covered and unfixed, applying the single
+//placement which most improves the local score, until none does
+func (st *fixedNonceState) drag() {
+	sparse := make([]bool, len(st.keystream))
+	for i := range sparse {
+		sparse[i] = !st.fixed[i] && st.coverage(i) <= sparseCoverage
+	}
+	words := dragWords()
+	for iter := 0; iter < len(st.keystream); iter++ {
+		bestGain := 0.0
+		var bestKs []byte
+		bestOffset := 0
+		for _, c := range st.ctexts {
+			for _, w := range words {
+			offsets:
+				for off := 0; off+len(w) <= len(c); off++ {
+					for i := off; i < off+len(w); i++ {
+						if !sparse[i] {
+							continue offsets
+						}
+					}
+					old := append([]byte{}, st.keystream[off:off+len(w)]...)
+					before := st.localScore(off, off+len(w))
+					for i := range w {
+						st.keystream[off+i] = c[off+i] ^ w[i]
+					}
+					gain := before - st.localScore(off, off+len(w))
+					if gain > bestGain {
+						bestGain = gain
+						bestKs = append([]byte{}, st.keystream[off:off+len(w)]...)
+						bestOffset = off
+					}
+					copy(st.keystream[off:], old)
+				}
+			}
+		}
+		if bestKs == nil {
+			return
+		}
+		copy(st.keystream[bestOffset:], bestKs)
+	}
+}
+
+//BreakFixedNonceCTR recovers the keystream shared by a set of
+//ciphertexts, such as CTR encryptions under a fixed key and nonce,
+//and returns it along with the plaintexts. The keystream covers the
+//longest ciphertext. Each keystream byte is first guessed by scoring
+//its column of ciphertext bytes. If the scorer is a ContextScorer
+//using context, each byte is then refined by scoring the plaintexts
+//around it, and common words are crib-dragged across columns covered
+//by few ciphertexts, where single bytes are hard to decide alone.
+//Any cribs given fix the keystream where they lie. Bytes in columns
+//covered by a single ciphertext are a best guess at plausible text.
+func BreakFixedNonceCTR(ctexts [][]byte, scorer scoring.Scorer, cribs ...Crib) (keystream []byte, ptexts [][]byte) {
+	maxLen := 0
+	for _, c := range ctexts {
+		if len(c) > maxLen {
+			maxLen = len(c)
+		}
+	}
+	st := &fixedNonceState{
+		ctexts:    ctexts,
+		keystream: make([]byte, maxLen),
+		fixed:     make([]bool, maxLen),
+		scorer:    scorer,
+	}
+
+	for _, cr := range cribs {
+		if cr.Line < 0 || cr.Line >= len(ctexts) || cr.Offset < 0 {
+			continue
+		}
+		c := ctexts[cr.Line]
+		for i, b := range cr.Text {
+			if cr.Offset+i >= len(c) {
+				break
+			}
+			st.keystream[cr.Offset+i] = c[cr.Offset+i] ^ b
+			st.fixed[cr.Offset+i] = true
+		}
+	}
+
+	for i := range st.keystream {
+		if st.fixed[i] {
+			continue
+		}
+		col := st.column(i)
+		bestScore := math.Inf(1)
+		for v := 0; v < 256; v++ {
+			ptext := make([]byte, len(col))
+			for j, b := range col {
+				ptext[j] = b ^ byte(v)
+			}
+			if score := scorer.Score(ptext); score < bestScore {
+				bestScore = score
+				st.keystream[i] = byte(v)
+			}
+		}
+	}
+
+	if cs, ok := scorer.(scoring.ContextScorer); ok && cs.UsesContext() {
+		st.refine()
+		st.drag()
+		st.refine()
+	}
+
+	ptexts = make([][]byte, len(ctexts))
+	for i, c := range ctexts {
+		ptexts[i] = make([]byte, len(c))
+		for j, b := range c {
+			ptexts[i][j] = b ^ st.keystream[j]
+		}
+	}
+	return st.keystream, ptexts
+}
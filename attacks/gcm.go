@@ -0,0 +1,87 @@
+//This file contains the nonce-reuse ("forbidden") attack on GCM
+
+package attacks
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/mathutil"
+)
+
+//GCMMessage is a message sealed with GCM: its additional data,
+//ciphertext and tag
+type GCMMessage struct {
+	AAD   []byte
+	Ctext []byte
+	Tag   []byte
+}
+
+//GCMAuthKey is a candidate GCM authentication key H, along with the
+//mask E_K(J0) which the key and nonce put on every tag. Together they
+//are enough to compute the tag of any message under that nonce.
+type GCMAuthKey struct {
+	H    mathutil.GF128
+	Mask []byte
+}
+
+//Tag returns the full 16-byte tag of a message under the key and nonce
+func (k GCMAuthKey) Tag(aad, ctext []byte) []byte {
+	tag, _ := bytesutil.XorBufs(ciphers.GHASH(k.H, aad, ctext), k.Mask)
+	return tag
+}
+
+//gcmTagPoly returns the polynomial in H which is zero at the true
+//authentication key: GHASH of the message, plus its tag, which leaves
+//the mask as the constant term. The masks cancel between two messages
+//under the same nonce.
+func gcmTagPoly(m GCMMessage) mathutil.GF128Poly {
+	blocks := ciphers.GHASHBlocks(m.AAD, m.Ctext)
+	n := len(blocks)
+	p := make(mathutil.GF128Poly, n+1)
+	p[0] = mathutil.GF128FromBytes(m.Tag)
+	for i, b := range blocks {
+		p[n-i] = b
+	}
+	return p
+}
+
+//RecoverGCMAuthKeys recovers the authentication key from two or more
+//messages sealed with GCM under the same key and nonce, with full
+//16-byte tags. The difference of the first two messages' tag
+//polynomials is factored to find the candidate keys, and the other
+//messages rule out candidates. Usually one candidate is left; if not,
+//try forgeries with each. Returns a non-nil error if there are fewer
+//than two messages, a tag is truncated, or no candidate fits.
+func RecoverGCMAuthKeys(msgs ...GCMMessage) ([]GCMAuthKey, error) {
+	if len(msgs) < 2 {
+		return nil, errors.New("Need at least two messages")
+	}
+	for _, m := range msgs {
+		if len(m.Tag) != 16 {
+			return nil, errors.New("Tags must be 16 bytes")
+		}
+	}
+	base := gcmTagPoly(msgs[0])
+	roots := base.Add(gcmTagPoly(msgs[1])).Roots()
+
+	keys := []GCMAuthKey{}
+candidates:
+	for _, h := range roots {
+		//the mask is the constant term left over at the true key
+		key := GCMAuthKey{H: h}
+		key.Mask, _ = bytesutil.XorBufs(ciphers.GHASH(h, msgs[0].AAD, msgs[0].Ctext), msgs[0].Tag)
+		for _, m := range msgs[1:] {
+			if !bytes.Equal(key.Tag(m.AAD, m.Ctext), m.Tag) {
+				continue candidates
+			}
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("No candidate key fits every message")
+	}
+	return keys, nil
+}
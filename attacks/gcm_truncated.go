@@ -0,0 +1,232 @@
+//This file contains Ferguson's attack on GCM with truncated tags,
+//which recovers the authentication key from forgery attempts
+
+package attacks
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+
+	"github.com/alanese/cryptopals/mathutil"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//gcmErrorMatrices holds, for every bit of the error blocks, the first
+//tag-size rows of the matrix taking H to that bit's contribution to
+//the tag
+type gcmErrorMatrices struct {
+	n, tagBits int
+	//rows[(i-1)*128+b][r] is row r of the matrix for error block
+	//d_i = x^b, as a vector over GF(2)
+	rows [][]mathutil.GF128
+}
+
+//newGCMErrorMatrices computes the matrices for n error blocks.
+//Error block d_i goes on the ciphertext block multiplied by H^(2^i),
+//so it changes the tag by d_i * H^(2^i). Squaring is linear over
+//GF(2), so this is a linear function of H, and so is the sum over i.
+func newGCMErrorMatrices(n, tagBits int) *gcmErrorMatrices {
+	e := &gcmErrorMatrices{n, tagBits, make([][]mathutil.GF128, n*128)}
+	//sq[j] is (x^j)^(2^i), column j of the i-th power of squaring
+	sq := make([]mathutil.GF128, 128)
+	for j := range sq {
+		sq[j] = mathutil.GF128Monomial(j)
+	}
+	for i := 1; i <= n; i++ {
+		for j := range sq {
+			sq[j] = sq[j].Square()
+		}
+		for b := 0; b < 128; b++ {
+			xb := mathutil.GF128Monomial(b)
+			rows := make([]mathutil.GF128, tagBits)
+			for j, s := range sq {
+				col := xb.Mul(s)
+				for r := range rows {
+					if col.Coeff(r) == 1 {
+						rows[r] = rows[r].Add(mathutil.GF128Monomial(j))
+					}
+				}
+			}
+			e.rows[(i-1)*128+b] = rows
+		}
+	}
+	return e
+}
+
+//errorRows returns the first tag-size rows of the matrix for the
+//error blocks set in d, a vector of n*128 bits
+func (e *gcmErrorMatrices) errorRows(d mathutil.BitVector) []mathutil.GF128 {
+	rows := make([]mathutil.GF128, e.tagBits)
+	for v := range e.rows {
+		if d.Get(v) == 0 {
+			continue
+		}
+		for r, row := range e.rows[v] {
+			rows[r] = rows[r].Add(row)
+		}
+	}
+	return rows
+}
+
+//dependencyMatrix returns the matrix whose kernel is the set of error
+//blocks d for which the first k rows of the error matrix vanish on
+//every key left in the span of basis. Each of its k*len(basis) rows is
+//one entry of that product, as a linear function of the bits of d.
+func (e *gcmErrorMatrices) dependencyMatrix(basis []mathutil.GF128, k int) []mathutil.BitVector {
+	t := make([]mathutil.BitVector, k*len(basis))
+	for i := range t {
+		t[i] = mathutil.NewBitVector(len(e.rows))
+	}
+	for v, rows := range e.rows {
+		for r := 0; r < k; r++ {
+			for c, x := range basis {
+				if rows[r].Dot(x) == 1 {
+					t[r*len(basis)+c].Set(v)
+				}
+			}
+		}
+	}
+	return t
+}
+
+//gcmKeyBasis returns a basis of the keys satisfying every equation,
+//each a vector dotted with H to give zero
+func gcmKeyBasis(eqs []mathutil.GF128) []mathutil.GF128 {
+	rows := make([]mathutil.BitVector, len(eqs))
+	for i, eq := range eqs {
+		rows[i] = gf128ToBits(eq)
+	}
+	basis := []mathutil.GF128{}
+	for _, v := range mathutil.GF2Kernel(rows, 128) {
+		basis = append(basis, bitsToGF128(v, 0))
+	}
+	return basis
+}
+
+//gf128ToBits returns a as a vector of 128 bits
+func gf128ToBits(a mathutil.GF128) mathutil.BitVector {
+	v := mathutil.NewBitVector(128)
+	for j := 0; j < 128; j++ {
+		if a.Coeff(j) == 1 {
+			v.Set(j)
+		}
+	}
+	return v
+}
+
+//bitsToGF128 returns bits from to from+127 of v as an element
+func bitsToGF128(v mathutil.BitVector, from int) mathutil.GF128 {
+	var a mathutil.GF128
+	for j := 0; j < 128; j++ {
+		if v.Get(from+j) == 1 {
+			a = a.Add(mathutil.GF128Monomial(j))
+		}
+	}
+	return a
+}
+
+//randomCombination returns a random nonzero sum of basis vectors,
+//or nil if the basis is empty
+func randomCombination(basis []mathutil.BitVector, n int) mathutil.BitVector {
+	if len(basis) == 0 {
+		return nil
+	}
+	for {
+		d := mathutil.NewBitVector(n)
+		for _, b := range basis {
+			if rand.Intn(2) == 1 {
+				d.Xor(b)
+			}
+		}
+		if !d.IsZero() {
+			return d
+		}
+	}
+}
+
+//RecoverGCMAuthKeyTruncated recovers the authentication key H of GCM
+//with a short tag, given a valid message and an oracle that checks
+//tags under the same key and nonce (challenge 64, after Ferguson).
+//
+//Forgeries change only the ciphertext blocks multiplied by H^2, H^4,
+//..., H^(2^n), so the tag error is linear in H. Error blocks are
+//chosen from the kernel of a dependency matrix so that the first k
+//bits of the error are zero whatever H is, leaving 2^-(t-k) odds of
+//acceptance for a t-bit tag. Each accepted forgery says the remaining
+//t-k error bits are zero at H, which are linear equations on H. These
+//shrink the space of possible keys, so more tag bits can be zeroed on
+//the next round, until a single key is left.
+//
+//The ciphertext must be whole blocks; with m blocks, n is the largest
+//with 2^n <= m+1, and more blocks means fewer queries. Stops after
+//maxQueries queries if it is positive. Returns a non-nil error if the
+//ciphertext is unusable, the tag is empty, the query limit is hit, or
+//the forgeries are inconsistent.
+func RecoverGCMAuthKeyTruncated(oracle oracles.GCMOracle, aad, ctext, tag []byte, maxQueries int, verbose bool) (mathutil.GF128, error) {
+	m := len(ctext) / 16
+	if len(ctext)%16 != 0 || m == 0 {
+		return mathutil.GF128{}, errors.New("Ciphertext must be a nonzero number of whole blocks")
+	}
+	if len(tag) == 0 || len(tag) > 16 {
+		return mathutil.GF128{}, errors.New("Tag must be from 1 to 16 bytes")
+	}
+	n := bits.Len(uint(m+1)) - 1
+	tagBits := 8 * len(tag)
+	e := newGCMErrorMatrices(n, tagBits)
+
+	forged := append([]byte{}, ctext...)
+	applyError := func(d mathutil.BitVector) {
+		for i := 1; i <= n; i++ {
+			block := forged[(m+1-(1<<i))*16:]
+			for j, c := range bitsToGF128(d, (i-1)*128).Bytes() {
+				block[j] ^= c
+			}
+		}
+	}
+
+	eqs := []mathutil.GF128{}
+	basis := gcmKeyBasis(eqs)
+	queries := 0
+	for len(basis) > 1 {
+		r := len(basis)
+		k := (n*128 - 1) / r
+		if k > tagBits-1 {
+			k = tagBits - 1
+		}
+		kernel := mathutil.GF2Kernel(e.dependencyMatrix(basis, k), n*128)
+		if verbose {
+			fmt.Printf("%d key bits unknown: zeroing %d of %d tag bits with %d free error bits\n", r, k, tagBits, len(kernel))
+		}
+		for {
+			if maxQueries > 0 && queries >= maxQueries {
+				return mathutil.GF128{}, errors.New("Query limit reached")
+			}
+			d := randomCombination(kernel, n*128)
+			if d == nil {
+				return mathutil.GF128{}, errors.New("No usable error blocks")
+			}
+			applyError(d)
+			ok := oracle.ValidTag(aad, forged, tag)
+			applyError(d)
+			queries++
+			if !ok {
+				continue
+			}
+			if verbose {
+				fmt.Printf("Forgery accepted after %d queries\n", queries)
+			}
+			eqs = append(eqs, e.errorRows(d)[k:]...)
+			break
+		}
+		basis = gcmKeyBasis(eqs)
+	}
+	if len(basis) == 0 {
+		return mathutil.GF128{}, errors.New("Forgeries inconsistent with any key")
+	}
+	if verbose {
+		fmt.Printf("Recovered H after %d queries\n", queries)
+	}
+	return basis[0], nil
+}
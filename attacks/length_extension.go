@@ -0,0 +1,71 @@
+//This file contains length extension of secret-prefix MACs over any
+//Merkle-Damgard hash
+
+package attacks
+
+import (
+	"errors"
+
+	"github.com/alanese/cryptopals/hashes"
+)
+
+//LengthExtension is a forged message and MAC for one guess at the
+//secret length
+type LengthExtension struct {
+	SecretLen int
+	Message   []byte
+	Digest    []byte
+}
+
+//ExtendMAC extends a secret-prefix MAC H(secret||message), assuming
+//the secret is secretLen bytes long. The forged message is the
+//original message, plus the glue padding H put after secret||message,
+//plus suffix; the digest continues hashing from the original one, so
+//the secret is never needed. Returns a non-nil error if the digest is
+//the wrong length for h.
+func ExtendMAC(h *hashes.MDHash, message, digest, suffix []byte, secretLen int) (LengthExtension, error) {
+	prefixLen := uint64(secretLen + len(message))
+	d, err := h.NewFromDigest(digest, prefixLen)
+	if err != nil {
+		return LengthExtension{}, err
+	}
+	d.Write(suffix)
+	forged := append(append(append([]byte{}, message...), h.Padding(prefixLen)...), suffix...)
+	return LengthExtension{SecretLen: secretLen, Message: forged, Digest: d.Sum(nil)}, nil
+}
+
+//ExtendMACRange extends a secret-prefix MAC as ExtendMAC for every
+//secret length from minLen to maxLen inclusive, one of which is right
+//if the secret's length is in that range. Returns a non-nil error if
+//the range is invalid or the digest is the wrong length for h.
+func ExtendMACRange(h *hashes.MDHash, message, digest, suffix []byte, minLen, maxLen int) ([]LengthExtension, error) {
+	if minLen < 0 || maxLen < minLen {
+		return nil, errors.New("Invalid secret length range")
+	}
+	forgeries := make([]LengthExtension, 0, maxLen-minLen+1)
+	for l := minLen; l <= maxLen; l++ {
+		f, err := ExtendMAC(h, message, digest, suffix, l)
+		if err != nil {
+			return nil, err
+		}
+		forgeries = append(forgeries, f)
+	}
+	return forgeries, nil
+}
+
+//ForgePrefixMAC extends a secret-prefix MAC for secret lengths up to
+//maxSecretLen, returning the first forgery the verifier accepts.
+//Returns a non-nil error if none is accepted or the digest is the
+//wrong length.
+func ForgePrefixMAC(h *hashes.MDHash, v hashes.Verifier, message, digest, suffix []byte, maxSecretLen int) (LengthExtension, error) {
+	forgeries, err := ExtendMACRange(h, message, digest, suffix, 0, maxSecretLen)
+	if err != nil {
+		return LengthExtension{}, err
+	}
+	for _, f := range forgeries {
+		if v.Verify(f.Message, f.Digest) {
+			return f, nil
+		}
+	}
+	return LengthExtension{}, errors.New("No forgery accepted")
+}
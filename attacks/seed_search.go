@@ -0,0 +1,203 @@
+//This file contains a parallel brute-force search for the seeds of
+//PRNGs, such as those seeded from the clock
+
+package attacks
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alanese/cryptopals/prng"
+)
+
+//SeedGenerator is a PRNG whose seeds SeedSearch can try, along with
+//what it calls an output
+type SeedGenerator int
+
+const (
+	//SeedMT19937 is prng.NewTwister(uint32(seed)), with outputs from Next
+	SeedMT19937 SeedGenerator = iota
+	//SeedGoMathRand is Go's math/rand.NewSource(seed), with outputs
+	//from its Uint64. Int63 is that without the top bit, and Int31
+	//is bits 32 to 62 of it.
+	SeedGoMathRand
+	//SeedGlibc is glibc's srand(uint32(seed)), with 31-bit outputs
+	//from rand()
+	SeedGlibc
+	//SeedJava is Java's new Random(seed), with 32-bit outputs from
+	//nextInt()
+	SeedJava
+)
+
+//String returns the generator's name
+func (g SeedGenerator) String() string {
+	switch g {
+	case SeedMT19937:
+		return "mt19937"
+	case SeedGoMathRand:
+		return "math/rand"
+	case SeedGlibc:
+		return "glibc"
+	case SeedJava:
+		return "java"
+	}
+	return "unknown"
+}
+
+//outputs returns a function producing the generator's outputs after
+//seeding with seed
+func (g SeedGenerator) outputs(seed int64) func() uint64 {
+	switch g {
+	case SeedMT19937:
+		t := prng.NewTwister(uint32(seed))
+		return func() uint64 { return uint64(t.Next()) }
+	case SeedGoMathRand:
+		s := rand.NewSource(seed).(rand.Source64)
+		return s.Uint64
+	case SeedGlibc:
+		r := prng.NewGlibcRand(uint32(seed))
+		return func() uint64 { return uint64(r.Next()) }
+	case SeedJava:
+		r := prng.NewJavaRandom(seed)
+		return func() uint64 { return uint64(r.NextBits(32)) }
+	}
+	return nil
+}
+
+//SeedObservation is an observed output: the bits set in Mask have the
+//values they have in Value. A zero Mask is an output not seen at all.
+type SeedObservation struct {
+	Mask  uint64
+	Value uint64
+}
+
+//SeedOutputs returns observations of whole outputs
+func SeedOutputs(values ...uint64) []SeedObservation {
+	obs := make([]SeedObservation, len(values))
+	for i, v := range values {
+		obs[i] = SeedObservation{^uint64(0), v}
+	}
+	return obs
+}
+
+//SeedMatch is a seed reproducing the observations, starting with the
+//output Offset places after the first
+type SeedMatch struct {
+	Seed   int64
+	Offset int
+}
+
+//SeedSearch searches the seeds From up to To (exclusive) of a
+//generator for those which reproduce a run of consecutive observed
+//outputs, starting anywhere in the first MaxOffset+1 outputs. The
+//seeds are shared out over Workers goroutines, or one per CPU if
+//Workers is 0.
+type SeedSearch struct {
+	Generator SeedGenerator
+	Observed  []SeedObservation
+	From, To  int64
+	MaxOffset int
+	Workers   int
+}
+
+//TimeSeeds returns the seeds From and To for a clock-seeded generator
+//seeded between start and end inclusive, where the seed counts units
+//since the Unix epoch: time.Second for time(NULL), time.Millisecond for
+//Java's currentTimeMillis, or time.Nanosecond for Go's UnixNano
+func TimeSeeds(start, end time.Time, unit time.Duration) (int64, int64) {
+	return start.UnixNano() / int64(unit), end.UnixNano()/int64(unit) + 1
+}
+
+//seedSearchChunk is how many seeds a worker takes at a time
+const seedSearchChunk = 1024
+
+//Run returns every matching seed and offset, ordered by seed and
+//offset. Returns a non-nil error if there is nothing observed, the
+//range is empty, or the generator is unknown.
+func (s SeedSearch) Run() ([]SeedMatch, error) {
+	if s.Generator.outputs(0) == nil {
+		return nil, errors.New("Unknown generator")
+	}
+	first := -1
+	for i, o := range s.Observed {
+		if o.Mask != 0 {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return nil, errors.New("No outputs observed")
+	}
+	if s.To <= s.From {
+		return nil, errors.New("Empty seed range")
+	}
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var next atomic.Int64
+	next.Store(s.From)
+	var mu sync.Mutex
+	matches := []SeedMatch{}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]uint64, s.MaxOffset+len(s.Observed))
+			for {
+				lo := next.Add(seedSearchChunk) - seedSearchChunk
+				if lo >= s.To || lo < s.From {
+					//done, or the counter wrapped past the top of the range
+					return
+				}
+				hi := min(lo+seedSearchChunk, s.To)
+				if hi < lo {
+					hi = s.To
+				}
+				for seed := lo; seed < hi; seed++ {
+					gen := s.Generator.outputs(seed)
+					for i := range buf {
+						buf[i] = gen()
+					}
+					for off := 0; off <= s.MaxOffset; off++ {
+						if s.matchAt(buf[off:], first) {
+							mu.Lock()
+							matches = append(matches, SeedMatch{seed, off})
+							mu.Unlock()
+						}
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Seed != matches[j].Seed {
+			return matches[i].Seed < matches[j].Seed
+		}
+		return matches[i].Offset < matches[j].Offset
+	})
+	return matches, nil
+}
+
+//matchAt reports whether outputs match the observations, checking the
+//first observed one before the rest
+func (s SeedSearch) matchAt(outputs []uint64, first int) bool {
+	o := s.Observed[first]
+	if outputs[first]&o.Mask != o.Value&o.Mask {
+		return false
+	}
+	for i, o := range s.Observed[first+1:] {
+		if outputs[first+1+i]&o.Mask != o.Value&o.Mask {
+			return false
+		}
+	}
+	return true
+}
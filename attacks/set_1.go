@@ -0,0 +1,137 @@
+package attacks
+
+import (
+	"math"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/scoring"
+)
+
+//DetectAESECB attempts to detect whether a byte slice
+//has likely been encrypted with AES-ECB.
+//False positives are very unlikely; false negatives not so much.
+func DetectAESECB(ctext []byte) bool {
+	return DetectECB(ctext, 16)
+}
+
+//DetectECB is DetectAESECB for a block cipher
+//with the given block size
+func DetectECB(ctext []byte, blockSize int) bool {
+	for i := 0; i < blockSize && i < len(ctext); i++ {
+		c := bytesutil.Chunkify(ctext[i:], blockSize)
+		if bytesutil.ContainsDuplicates(c) {
+			return true
+		}
+	}
+	return false
+}
+
+//GuessRepeatedXorKeyLen guesses the length of the key
+//for a ciphertext encrypted with repeating-key XOR
+func GuessRepeatedXorKeyLen(ctext []byte, min, max int) int {
+	if max > 2*len(ctext) {
+		panic("Insufficient data for given max")
+	}
+	if min > max {
+		panic("Min must be less than max")
+	}
+	bestDist := 8.0
+	var bestLen int
+	for i := min; i <= max; i++ {
+		distSum := 0.0
+		j := 0
+		testSlice := ctext
+		for len(testSlice) > 2*i {
+			block1 := testSlice[:i]
+			block2 := testSlice[i : 2*i]
+			d, _ := bytesutil.NormalizedEditDistance(block1, block2)
+			distSum += d
+			j++
+			testSlice = testSlice[2*i:]
+		}
+		distSum = distSum / float64(j)
+		if distSum < bestDist {
+			bestDist = distSum
+			bestLen = i
+		}
+	}
+
+	return bestLen
+}
+
+//BreakSingleByteXor attempts to decrypt a byte slice
+//encrypted with single-byte XOR, choosing the key whose
+//plaintext the scorer rates best
+func BreakSingleByteXor(ctext []byte, scorer scoring.Scorer) []byte {
+	minScore := math.Inf(1)
+	var bestPtext []byte
+	var curPtext []byte
+	var curScore float64
+	for i := 0; i < 256; i++ {
+		curPtext = ciphers.XorEncrypt(ctext, []byte{byte(i)})
+		curScore = scorer.Score(curPtext)
+		if curScore < minScore {
+			minScore = curScore
+			bestPtext = curPtext
+		}
+	}
+
+	return bestPtext
+}
+
+//BreakKnownLenRepeatedXor attempts to decrypt a byte slice
+//encrypted with repeated-key XOR with a known key length.
+//Each key byte is guessed from its column alone; if the scorer
+//is a ContextScorer using context, the key is then refined by
+//scoring the whole plaintext.
+func BreakKnownLenRepeatedXor(ctext []byte, keyLen int, scorer scoring.Scorer) []byte {
+	key := make([]byte, keyLen)
+	for i := range key {
+		tmp := bytesutil.EveryNth(ctext, i, keyLen)
+		if len(tmp) > 0 {
+			key[i] = tmp[0] ^ BreakSingleByteXor(tmp, scorer)[0]
+		}
+	}
+	if cs, ok := scorer.(scoring.ContextScorer); ok && cs.UsesContext() {
+		key = RefineXorKey(ctext, key, scorer)
+	}
+	return ciphers.XorEncrypt(ctext, key)
+}
+
+//RefineXorKey improves a guessed repeating XOR key by coordinate
+//descent: each key byte in turn is replaced by the value giving the
+//best-scoring whole plaintext, until no single change helps.
+//Returns the refined key; key itself is not modified.
+func RefineXorKey(ctext, key []byte, scorer scoring.Scorer) []byte {
+	key = append([]byte{}, key...)
+	bestScore := scorer.Score(ciphers.XorEncrypt(ctext, key))
+	for pass := 0; pass < 8; pass++ {
+		changed := false
+		for i := range key {
+			orig := key[i]
+			for v := 0; v < 256; v++ {
+				key[i] = byte(v)
+				if score := scorer.Score(ciphers.XorEncrypt(ctext, key)); score < bestScore {
+					bestScore = score
+					orig = byte(v)
+					changed = true
+				}
+			}
+			key[i] = orig
+		}
+		if !changed {
+			break
+		}
+	}
+	return key
+}
+
+//BreakRepeatedXor attempts to decrypt a byte slice
+//encrypted with repeated-key XOR with unknown key length,
+//choosing the plaintext the scorer rates best. Assumes the
+//key length is between 2 and 64 bytes inclusive.
+func BreakRepeatedXor(ctext []byte, scorer scoring.Scorer) []byte {
+	keyLen := GuessRepeatedXorKeyLen(ctext, 2, 64)
+	return BreakKnownLenRepeatedXor(ctext, keyLen, scorer)
+}
@@ -0,0 +1,87 @@
+package attacks
+
+import (
+	"strings"
+
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//BreakMysteryEncrypt uncovers the MYSTERY TEXT appended to
+//the input by an ECB oracle such as C12Oracle. It is
+//BreakECBSuffix without the extra results; returns nil
+//if the attack fails.
+func BreakMysteryEncrypt(oracle oracles.ECBOracle) []byte {
+	res, err := BreakECBSuffix(oracle, false)
+	if err != nil {
+		return nil
+	}
+	return res.Secret
+}
+
+//BreakMysteryEncryptHard uncovers MYSTERY TEXT added
+//by an ECB oracle which also prepends secret padding, such
+//as C14Oracle. BreakECBSuffix handles the padding, whatever its
+//length, so this is the same as BreakMysteryEncrypt.
+func BreakMysteryEncryptHard(oracle oracles.ECBOracle) []byte {
+	return BreakMysteryEncrypt(oracle)
+}
+
+//C13Layout is the layout of the profiles made by ProfileFor,
+//with the usual 16 hex digits of uid
+var C13Layout = ECBTokenLayout{
+	Before:  "email=",
+	After:   "&uid=" + strings.Repeat("\x00", 16) + "&role=user",
+	Unknown: 0,
+	Escape:  oracles.ProfileEscape,
+}
+
+//CreateEncryptedAdminProfile uses ProfileFor
+//and EncryptProfile to construct a profile
+//with the admin role, using ECBCutPaste with C13Layout.
+//This will fail roughly 1 in 8 attempts, when the uid
+//is shorter than 16 hex digits. The forged profile is
+//padded with PKCS#7.
+func CreateEncryptedAdminProfile(key []byte) []byte {
+	forger := ECBCutPaste{
+		Oracle: oracles.ECBOracleFunc(func(ptext []byte) []byte {
+			return oracles.EncryptProfile(oracles.ProfileFor(string(ptext)), key)
+		}),
+		Layout:    C13Layout,
+		BlockSize: 16,
+	}
+	adminEProfile, err := forger.ReplaceAfter("tom@example.com", "role=", "admin")
+	if err != nil {
+		return nil
+	}
+	return adminEProfile
+}
+
+//c16UserdataOffset is where Challenge16Func and Challenge26Func
+//put the userdata in the plaintext
+const c16UserdataOffset = 32
+
+//Challenge16ForgeData creates a byte slice in the format
+//output by Challenge16Func which, when decrypted, contains
+//the text ";admin=true;" using a CBC bit-flipping attack.
+//Only the userdata is known, so the attack flips bits of the
+//block before it, which is scrambled.
+func Challenge16ForgeData(key, iv []byte) []byte {
+	userdata := "aaaaaaaaaaaaaaaa"
+	ctext := oracles.Challenge16Func(userdata, key, iv)
+	flip := BitFlip{
+		Mode:      FlipCBC,
+		Ctext:     ctext,
+		Ptext:     make([]byte, len(ctext)),
+		Known:     make([]bool, len(ctext)),
+		BlockSize: 16,
+	}
+	for i := range userdata {
+		flip.Ptext[c16UserdataOffset+i] = userdata[i]
+		flip.Known[c16UserdataOffset+i] = true
+	}
+	res, err := flip.Apply(FlipEdit{c16UserdataOffset + 5, []byte(";admin=true")})
+	if err != nil {
+		return nil
+	}
+	return res.Ctext
+}
@@ -0,0 +1,248 @@
+package attacks
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/oracles"
+	"github.com/alanese/cryptopals/prng"
+	"github.com/alanese/cryptopals/scoring"
+)
+
+//Challenge17GetPrevByte , given a ciphertext and a known suffix
+//of the plaintext, finds the plaintext byte preceding the known
+//suffix using a padding oracle attack. The block size is taken
+//to be the length of iv; if cText is a single block, the IV is
+//manipulated in place of the previous ciphertext block.
+func Challenge17GetPrevByte(cText, knownBytes, iv []byte, oracle oracles.CBCPaddingOracle) byte {
+	blockSize := len(iv)
+	full := append(append([]byte{}, iv...), cText...)
+	testLength := len(knownBytes) + 1
+	head := full[0 : len(full)-testLength-blockSize]
+	mid := full[len(full)-testLength-blockSize : len(full)-blockSize]
+	tail := full[len(full)-blockSize:]
+	flipper := append([]byte{byte(0)}, knownBytes...)
+	for i := 1; i < len(flipper); i++ {
+		flipper[i] = flipper[i] ^ byte(testLength)
+	}
+	testCtext := bytes.NewBuffer([]byte{})
+	for i := 0; i < 256; i++ {
+		flipper[0] = byte(i)
+		testMid, _ := bytesutil.XorBufs(mid, flipper)
+		_, _ = testCtext.Write(head)
+		_, _ = testCtext.Write(testMid)
+		_, _ = testCtext.Write(tail)
+		if challenge17Query(testCtext.Bytes(), blockSize, oracle) {
+			if testLength > 1 {
+				return byte(i) ^ byte(testLength)
+			}
+			//rule out a false positive from a plaintext ending in
+			//0x02 0x02 (or 0x03 0x03 0x03, etc.) by disturbing the
+			//second-to-last byte
+			testCtext.Reset()
+			_, _ = testCtext.Write(head[:len(head)-1])
+			_ = testCtext.WriteByte(head[len(head)-1] ^ byte(1))
+			_, _ = testCtext.Write(testMid)
+			_, _ = testCtext.Write(tail)
+			if challenge17Query(testCtext.Bytes(), blockSize, oracle) {
+				return byte(i) ^ byte(testLength)
+
+			}
+		}
+		testCtext.Reset()
+
+	}
+	panic("Found no valid bytes")
+}
+
+//challenge17Query splits an IV off the front of ivCtext
+//and passes both to the padding oracle
+func challenge17Query(ivCtext []byte, blockSize int, oracle oracles.CBCPaddingOracle) bool {
+	return oracle.ValidPadding(ivCtext[:blockSize], ivCtext[blockSize:])
+}
+
+//Challenge17GetLastBlock finds the last plaintext block
+//of the given ciphertext using a CBC padding oracle attack
+func Challenge17GetLastBlock(cText, iv []byte, oracle oracles.CBCPaddingOracle) []byte {
+	knownBytes := []byte{}
+	for i := 0; i < len(iv); i++ {
+		nextByte := Challenge17GetPrevByte(cText, knownBytes, iv, oracle)
+		knownBytes = append([]byte{nextByte}, knownBytes...)
+	}
+	return knownBytes
+}
+
+//Challenge18Decrypt decrypts the text from challenge 18.
+func Challenge18Decrypt() {
+	key := []byte("YELLOW SUBMARINE")
+	nonce := []byte{0, 0, 0, 0, 0, 0, 0, 0}
+	cText, _ := base64.StdEncoding.DecodeString("L77na/nrFsKvynd6HzOoG7GHTLXsTVu9qvY/2syLXzhPweyyMTJULu/6/kXX0KSvoOLSFQ==")
+	keystream := ciphers.GenerateCTRKeystream(6, key, nonce, ciphers.LittleEndianCounter)
+	keystream = keystream[:len(cText)]
+	pText, _ := bytesutil.XorBufs(keystream, cText)
+	fmt.Println(string(pText))
+
+}
+
+//Challenge20 breaks fixed-nonce CTR statistically as per challenge 20,
+//choosing plaintexts with the given scorer
+func Challenge20(sourceFname string, scorer scoring.Scorer) {
+	lines, _ := bytesutil.LinesFromFile(sourceFname)
+	decodedLines := [][]byte{}
+	for _, v := range lines {
+		if len(v) == 0 {
+			continue
+		}
+		line, _ := base64.StdEncoding.DecodeString(string(v))
+		decodedLines = append(decodedLines, line)
+	}
+
+	_, pTexts := BreakFixedNonceCTR(decodedLines, scorer)
+	for _, v := range pTexts {
+		fmt.Println(string(v))
+	}
+}
+
+//Challenge22BreakSeed creates a new Mersenne Twister with
+//a random seed near the current UNIX timestamp, then uses
+//the twister's first output to deduce the seed.
+//If Go's default random Source hasn't been seeded,
+//the random seed will be the same every time.
+func Challenge22BreakSeed() {
+	rightNow := time.Now()
+	target, secretSeed := oracles.Challenge22RandomNum()
+	from, to := TimeSeeds(rightNow.Add(-30*time.Second), rightNow.Add(1000*time.Second), time.Second)
+	search := SeedSearch{
+		Generator: SeedMT19937,
+		Observed:  SeedOutputs(uint64(target)),
+		From:      from,
+		To:        to,
+	}
+	matches, _ := search.Run()
+	for _, m := range matches {
+		fmt.Printf("Guessed %X\nActual  %X\n", uint32(m.Seed), secretSeed)
+	}
+}
+
+//C23UntemperRight11 inverts the tempering transformation
+//y ^= (y>>11)
+//TODO: generalize to variable shift distances
+func C23UntemperRight11(x uint32) uint32 {
+	top := x >> 21
+	mid := ((x & 0x001FFC00) >> 10) ^ top
+	bottom := (x & 0x000003FF) ^ (mid >> 1)
+	return (top << 21) | (mid << 10) | bottom
+}
+
+//C23UntemperRight18 inverts the tempering transformation
+//y ^= (y >> 18)
+//TODO: generalize to variable shift distances
+func C23UntemperRight18(x uint32) uint32 {
+	top := x >> 14
+	bottom := (x & 0x00003FFF) ^ (top >> 4)
+	return (top << 14) | bottom
+}
+
+//C23UntemperLeft inverts the tempering transformation
+//y ^= ((y << shift) & magicNum) for uint32's
+func C23UntemperLeft(x, magicNum uint32, shift int) uint32 {
+	chunks := []uint32{}
+	bitmask := bytesutil.RightOnes(shift)
+	tmp := x & bitmask
+	chunks = append(chunks, tmp)
+	x >>= shift
+	magicNum >>= shift
+	i := shift
+	for i < 32 {
+		tmp = (x ^ (chunks[len(chunks)-1] & magicNum)) & bitmask
+		chunks = append(chunks, tmp)
+		x >>= shift
+		magicNum >>= shift
+		i += shift
+	}
+	untempered := uint32(0)
+	for i, v := range chunks {
+		untempered |= (v << (i * shift))
+	}
+	return untempered
+}
+
+//TwisterUntemper inverts the tempering transformation
+//used in the Mersenne twister
+func TwisterUntemper(x uint32) uint32 {
+	x = C23UntemperRight18(x)
+	x = C23UntemperLeft(x, 0xEFC60000, 15)
+	x = C23UntemperLeft(x, 0x9D2C5680, 7)
+	x = C23UntemperRight11(x)
+	return x
+}
+
+//CloneTwister creates a clone of the given twister.
+//Assumes t.index is 0 or 624. Consumes 624 values from t.
+func CloneTwister(t *prng.Twister) *prng.Twister {
+	outputs := make([]uint32, 624)
+	for i := range outputs {
+		outputs[i] = t.Next()
+	}
+	cloned, _ := CloneTwisterOutputs(outputs)
+	return cloned
+}
+
+//CloneTwisterOutputs creates a clone of a twister from at least 624
+//consecutive outputs, the first of which was produced when the twister's
+//index was 0 or 624. Outputs past the first 624 are checked against the
+//clone, which is advanced past them. Returns a non-nil error if there are
+//too few outputs or the clone fails to reproduce the extra outputs.
+func CloneTwisterOutputs(outputs []uint32) (*prng.Twister, error) {
+	if len(outputs) < 624 {
+		return nil, fmt.Errorf("Need 624 outputs, got %v", len(outputs))
+	}
+	state := [624]uint32{}
+	for i := range state {
+		state[i] = TwisterUntemper(outputs[i])
+	}
+	cloned := prng.NewTwisterFromState(state)
+	for i, v := range outputs[624:] {
+		if cloned.Next() != v {
+			return nil, fmt.Errorf("Clone does not match output %v", 624+i)
+		}
+	}
+	return &cloned, nil
+}
+
+//C24RecoverKey recovers a (16-bit) key used to encrypt
+//a random prefix followed by a known plaintext
+func C24RecoverKey(key uint32) uint32 {
+	padLength := rand.Intn(5) + 5
+	pad := bytesutil.GenerateRandomByteSlice(padLength)
+	knownText := []byte("AAAAAAAAAAAAAA")
+	pText := append(pad, knownText...)
+	cText := ciphers.EncryptMT19937Stream(pText, key)
+	knownStart := len(pText) - len(knownText)
+
+	//EncryptMT19937Stream uses each output's bytes least significant
+	//first, so the known plaintext gives some bytes of some outputs
+	obs := make([]SeedObservation, (len(cText)+3)/4)
+	for i := knownStart; i < len(cText); i++ {
+		shift := 8 * (i % 4)
+		obs[i/4].Mask |= 0xFF << shift
+		obs[i/4].Value |= uint64(cText[i]^'A') << shift
+	}
+	search := SeedSearch{
+		Generator: SeedMT19937,
+		Observed:  obs,
+		From:      0,
+		To:        1 << 16,
+	}
+	matches, _ := search.Run()
+	if len(matches) == 0 {
+		return 0
+	}
+	return uint32(matches[0].Seed)
+
+}
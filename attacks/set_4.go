@@ -0,0 +1,256 @@
+package attacks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/hashes"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//C25BreakEdit uses C25Editor to break an AES-CTR encrypted
+//ciphertext via a chosen-plaintext attack.
+func C25BreakEdit(ctext, key []byte) []byte {
+	storage := bytesutil.NewMemFile(ctext)
+	ptext, _ := BreakCTREdit(storage, storage.Size(), oracles.C25Editor{Storage: storage, Key: key})
+	return ptext
+}
+
+//ctrEditChunk is how many bytes BreakCTREdit recovers per edit
+const ctrEditChunk = 1 << 16
+
+//BreakCTREdit recovers the plaintext of size bytes of CTR ciphertext
+//stored in ctext, given an oracle which edits the same storage. Each
+//chunk is overwritten with zeroes, which leaves the keystream in its
+//place, and then edited back to the recovered plaintext, so the
+//storage ends up as it started. Works a chunk at a time, so the
+//ciphertext can be a large file. Returns a non-nil error if reading
+//the storage or an edit fails.
+func BreakCTREdit(ctext io.ReaderAt, size int64, oracle oracles.CTREditOracle) ([]byte, error) {
+	ptext := make([]byte, size)
+	for off := int64(0); off < size; off += ctrEditChunk {
+		chunk := ptext[off:min(off+ctrEditChunk, size)]
+		if err := readFullAt(ctext, chunk, off); err != nil {
+			return nil, err
+		}
+		if err := oracle.Edit(off, make([]byte, len(chunk))); err != nil {
+			return nil, err
+		}
+		keystream := make([]byte, len(chunk))
+		if err := readFullAt(ctext, keystream, off); err != nil {
+			return nil, err
+		}
+		for i := range chunk {
+			chunk[i] ^= keystream[i]
+		}
+		if err := oracle.Edit(off, chunk); err != nil {
+			return nil, err
+		}
+	}
+	return ptext, nil
+}
+
+//readFullAt fills p from r at offset off, returning a non-nil
+//error only if p could not be filled
+func readFullAt(r io.ReaderAt, p []byte, off int64) error {
+	n, err := r.ReadAt(p, off)
+	if n == len(p) {
+		return nil
+	}
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+//Challenge26ForgeData creates a byte slice in the format
+//output by Challenge16Func which, when decrypted, contains
+//the text ";admin=true;" using a CTR bit-flipping attack
+//(C16 reimplemented with CTR). Nothing is scrambled.
+func Challenge26ForgeData(key []byte) []byte {
+	userdata := "aaaaaaaaaaaaaaaa"
+	ctext := oracles.Challenge26Func(userdata, key)
+	flip := BitFlip{
+		Mode:  FlipCTR,
+		Ctext: ctext,
+		Ptext: make([]byte, len(ctext)),
+		Known: make([]bool, len(ctext)),
+	}
+	for i := range userdata {
+		flip.Ptext[c16UserdataOffset+i] = userdata[i]
+		flip.Known[c16UserdataOffset+i] = true
+	}
+	res, err := flip.Apply(FlipEdit{c16UserdataOffset + 5, []byte(";admin=true")})
+	if err != nil {
+		return nil
+	}
+	return res.Ctext
+}
+
+//Challenge27ExtractKey uses Challenge27VerifyDecrypt to determine
+//the secret key
+func Challenge27ExtractKey(secretKey []byte) []byte {
+	pText := make([]byte, 48)
+	pText[0] = byte(128)
+	cText := ciphers.EncryptAESCBC(pText, secretKey, secretKey)
+	newCText := bytes.NewBuffer(cText[:16])
+	newCText.Write([]byte{0, 0, 0, 0, 0, 0, 0, 0})
+	newCText.Write([]byte{0, 0, 0, 0, 0, 0, 0, 0}) //write 16 zero bytes
+	newCText.Write(cText[:16])
+	extractedPtext, _ := oracles.Challenge27VerifyDecrypt(newCText.Bytes(), secretKey)
+	p1 := extractedPtext[0:16]
+	p3 := extractedPtext[32:48]
+	extractedKey, _ := bytesutil.XorBufs(p1, p3)
+	return extractedKey
+}
+
+//C29GluePadding generates the appropriate SHA-1 padding for
+//a message of the given length
+func C29GluePadding(length int) []byte {
+	return hashes.SHA1.Padding(uint64(length))
+}
+
+//C29ExtendMAC extends a secret-prefix SHA-1 MAC of message, assuming
+//the secret is secretLen bytes long. The forged message is the original
+//message, plus the glue padding, plus addedMsg; forgedHash is its MAC.
+func C29ExtendMAC(message, origDigest, addedMsg []byte, secretLen int) (forgedMsg, forgedHash []byte) {
+	f, err := ExtendMAC(hashes.SHA1, message, origDigest, addedMsg, secretLen)
+	if err != nil {
+		return nil, nil
+	}
+	return f.Message, f.Digest
+}
+
+//C29ForgeMAC generates a message/digest pair that will be validated
+//under a secret-prefix SHA-1 MAC. The generated message is the original
+//message, plus some padding, plus the text ";admin=true"
+//Assumes the secret key is at most 32 bytes
+func C29ForgeMAC(key, message, origDigest []byte) (forgedMsg, forgedHash []byte) {
+	valid := hashes.VerifierFunc(func(msg, digest []byte) bool { return oracles.C29ValidateMAC(key, msg, digest) })
+	f, err := ForgePrefixMAC(hashes.SHA1, valid, message, origDigest, []byte(";admin=true"), 32)
+	if err != nil {
+		return nil, nil
+	}
+	return f.Message, f.Digest
+}
+
+//C30GluePadding computes the padding bytes added
+//to a message of given length in hashing with MD4
+func C30GluePadding(length int) []byte {
+	return hashes.MD4.Padding(uint64(length))
+}
+
+//C30ForgeMAC forges a MAC/digest pair as per challenge 30
+//Assumes the secret key is at most 32 bytes
+func C30ForgeMAC(key, message, origDigest []byte) (forgedMsg, forgedHash []byte) {
+	valid := hashes.VerifierFunc(func(msg, digest []byte) bool { return oracles.C30ValidateMAC(key, msg, digest) })
+	f, err := ForgePrefixMAC(hashes.MD4, valid, message, origDigest, []byte(";admin=true"), 32)
+	if err != nil {
+		return nil, nil
+	}
+	return f.Message, f.Digest
+}
+
+//C31GetOverhead sends intentionally bad MACs for msg to the oracle
+//to determine the time taken by non-comparison parts of verification
+//(e.g. network travel times)
+func C31GetOverhead(oracle oracles.TimingOracle, msg []byte) time.Duration {
+	attempts := 5
+	fakeHmac := make([]byte, 20)
+
+	var overheadSum0 time.Duration
+	for i := 0; i < attempts; i++ {
+		_, t := oracle.TimedCheck(msg, fakeHmac)
+		overheadSum0 += t
+	}
+
+	fakeHmac[0] = 0x01
+	var overheadSum1 time.Duration
+	for i := 0; i < attempts; i++ {
+		_, t := oracle.TimedCheck(msg, fakeHmac)
+		overheadSum1 += t
+	}
+
+	if overheadSum0 > overheadSum1 {
+		return overheadSum1 / time.Duration(attempts)
+	}
+	return overheadSum0 / time.Duration(attempts)
+
+}
+
+//C31GetByteDelay estimates the per-byte compare time
+//for the insecure comparison in challenges 31/32
+func C31GetByteDelay(oracle oracles.TimingOracle, msg []byte, overhead time.Duration, verbose bool) time.Duration {
+	attemptsPerValue := 5
+	testHmac := make([]byte, 20)
+
+	var maxTime time.Duration
+
+	for i := 0; i < 256; i++ {
+		if verbose {
+			fmt.Printf("Delay testing: 0x%02X\n", i)
+		}
+		var totalTime time.Duration
+		testHmac[0] = byte(i)
+		for j := 0; j < attemptsPerValue; j++ {
+			_, t := oracle.TimedCheck(msg, testHmac)
+			totalTime += (t - overhead)
+		}
+		if totalTime > maxTime {
+			maxTime = totalTime
+		}
+	}
+	return maxTime / time.Duration(attemptsPerValue)
+}
+
+//C31BreakHash finds the HMAC-SHA1 signature for the given message
+//expected by a timing oracle, such as an oracles.HTTPTimingOracle for
+//a server or an oracles.SimulatedTimingOracle for an offline run.
+//Tested with a server running on localhost with an artificial delay of 2 ms per byte
+//compared. The verbose parameter determines whether status messages are printed to console.
+func C31BreakHash(oracle oracles.TimingOracle, msg []byte, verbose bool) []byte {
+	hmac := make([]byte, 20)
+	overhead := C31GetOverhead(oracle, msg)
+	if verbose {
+		fmt.Printf("Overhead %v\n", overhead)
+	}
+	byteDelay := C31GetByteDelay(oracle, msg, overhead, verbose)
+	if verbose {
+		fmt.Printf("Estimated byte delay %v\n", byteDelay)
+	}
+	if byteDelay <= 0 {
+		byteDelay = 1
+	}
+	i := 0
+	for {
+		hmac[i]++
+		if verbose {
+			fmt.Printf("Testing hash %X\n", hmac)
+		}
+		ok, delay := oracle.TimedCheck(msg, hmac)
+		if ok {
+			return hmac
+		}
+
+		//round to the nearest whole byte, since noise can push the
+		//delay either side of the true value
+		delay -= overhead
+		delayBlocks := int((delay + byteDelay/2) / byteDelay)
+
+		if verbose {
+			fmt.Printf("%v delay, %v bytes likely correct\n", delay, delayBlocks)
+		}
+
+		i = delayBlocks
+		if i < 0 {
+			i = 0
+		}
+		if i >= len(hmac) {
+			i = len(hmac) - 1
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package attacks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/mathutil"
+	"github.com/alanese/cryptopals/pubkey"
+)
+
+//C34Mallory implements a MITM attack on Diffie-Hellman key exchange.
+//Alice must initiate the key exchange, but once it is complete either
+//Alice or Bob can send a message; Mallory will read the message and pass it on
+//to the other party.  Run as a goroutine. Close either in channel to close both
+//out channels and terminate.
+func C34Mallory(aliceIn, aliceOut, bobIn, bobOut chan []byte) {
+	pBytes := <-aliceIn
+	gBytes := <-aliceIn
+	_ = <-aliceIn
+	bobOut <- pBytes
+	bobOut <- gBytes
+	bobOut <- pBytes
+	_ = <-bobIn
+	aliceOut <- pBytes
+	sharedSecret := big.NewInt(0)
+	secretHash := sha256.Sum256(sharedSecret.Bytes())
+	key := secretHash[:16]
+
+	for {
+		select {
+		case aliceMsg, ok := <-aliceIn:
+			if !ok {
+				close(aliceOut)
+				close(bobOut)
+				return
+			}
+			iv := aliceMsg[len(aliceMsg)-16:]
+			encrypted := aliceMsg[:len(aliceMsg)-16]
+			decrypted := ciphers.DecryptAESCBC(encrypted, key, iv)
+			decrypted, _ = ciphers.StripPKCS7Padding(decrypted, 16)
+			fmt.Printf("MALLORY: Intercepted message from Alice to Bob: %v\n", string(decrypted))
+			bobOut <- aliceMsg
+		case bobMsg, ok := <-bobIn:
+			if !ok {
+				close(aliceOut)
+				close(bobOut)
+				return
+			}
+			iv := bobMsg[len(bobMsg)-16:]
+			decrypted := ciphers.DecryptAESCBC(bobMsg[:len(bobMsg)-16], key, iv)
+			decrypted, _ = ciphers.StripPKCS7Padding(decrypted, 16)
+			fmt.Printf("MALLORY: Intercepted message from Bob to Alice: %v\n", string(decrypted))
+			aliceOut <- bobMsg
+
+		}
+	}
+
+}
+
+//C35Mallory implements a MITM attack on negotiated-group finite-field
+//Diffie-Hellman with a malicious g parameter. Run as a go-routine
+func C35Mallory(aliceIn, aliceOut, bobIn, bobOut chan []byte) {
+	p := big.NewInt(0).SetBytes(<-aliceIn)
+	<-aliceIn
+	//Choose one of the following two lines:
+	//g := big.NewInt(1)	//inject g=1
+	g := big.NewInt(0).SetBytes(p.Bytes()) //inject g=p
+	bobOut <- p.Bytes()
+	bobOut <- g.Bytes() //inject malicious g
+	tmp := <-bobIn
+	aliceOut <- tmp
+	tmp = <-bobIn
+	aliceOut <- tmp
+
+	A := <-aliceIn
+	bobOut <- A
+	B := <-bobIn
+	aliceOut <- B
+	key, _ := pubkey.DiffieHellmanKeys(g, p, p)
+	for {
+		select {
+		case v, ok := <-aliceIn:
+			if !ok {
+				close(aliceOut)
+				close(bobOut)
+				return
+			}
+			iv := v[len(v)-16:]
+			msg := v[:len(v)-16]
+			decrypted := ciphers.DecryptAESCBC(msg, key, iv)
+			decrypted, _ = ciphers.StripPKCS7Padding(decrypted, 16)
+			fmt.Printf("MALLORY: Intercepted message from Alice to Bob: %v\n", string(decrypted))
+			bobOut <- v
+
+		case v, ok := <-bobIn:
+			if !ok {
+				close(aliceOut)
+				close(bobOut)
+				return
+			}
+			iv := v[len(v)-16:]
+			msg := v[:len(v)-16]
+			decrypted := ciphers.DecryptAESCBC(msg, key, iv)
+			decrypted, _ = ciphers.StripPKCS7Padding(decrypted, 16)
+			fmt.Printf("MALLORY: Intercepted message from Bob to Alice: %v\n", string(decrypted))
+			aliceOut <- v
+		}
+	}
+
+}
+
+//C37BypassLogIn fools an improperly-safeguarded SRP password
+//verification scheme into granting access without knowing
+//the password
+func C37BypassLogIn(username string) bool {
+	genbBase := "http://localhost:8080/getB?u=%v&A=%X"
+	verifyBase := "http://localhost:8080/validate?u=%v&signature=%X"
+
+	A := big.NewInt(0)
+	genbresp, _ := http.Get(fmt.Sprintf(genbBase, username, A))
+	salt := make([]byte, 16)
+	genbresp.Body.Read(salt)
+
+	K := sha256.Sum256(A.Bytes())
+	hasher := hmac.New(sha256.New, K[:])
+
+	verifyHmac := hasher.Sum(salt)
+	verifyResp, _ := http.Get(fmt.Sprintf(verifyBase, username, verifyHmac))
+
+	return verifyResp.StatusCode == http.StatusOK
+}
+
+//C38MITM cracks a simplified SRP password with a MITM attack.
+//For simplicity, assumes the password is six lowercase letters
+func C38MITM(in, out chan []byte) {
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	b := pubkey.GenerateNISTDHPrivateKey1536(rnd)
+	B := pubkey.GenerateNISTDHPublicKey1536(b)
+	salt := bytesutil.GenerateRandomByteSlice(16)
+	u := big.NewInt(0).SetBytes(bytesutil.GenerateRandomByteSlice(16))
+
+	<-in //ignore username for this example
+	A := big.NewInt(0).SetBytes(<-in)
+	out <- salt
+	out <- B.Bytes()
+	out <- u.Bytes()
+
+	targetHmac := <-in
+
+	dhKey := big.NewInt(0).Exp(A, b, p)
+
+	testPW := []byte{97, 97, 97, 97, 97, 97}
+	for {
+		fmt.Printf("Testing password %v\n", string(testPW))
+		salted := append(salt, testPW...)
+		xH := sha256.Sum256(salted)
+		x := big.NewInt(0).SetBytes(xH[:])
+		exp := big.NewInt(0).Mul(u, x)
+		S := big.NewInt(0).Exp(B, exp, p)
+		S.Mul(S, dhKey)
+		S.Mod(S, p)
+		K := sha256.Sum256(S.Bytes())
+		hasher := hmac.New(sha256.New, K[:])
+		testHmac := hasher.Sum(salt)
+		if hmac.Equal(targetHmac, testHmac) {
+			fmt.Printf("Found password: %v\n", string(testPW))
+			out <- []byte("ERROR")
+			return
+		}
+		testPW[0]++
+		if testPW[0] > 122 {
+			testPW[0] = 97
+			testPW[1]++
+			if testPW[1] > 122 {
+				testPW[1] = 97
+				testPW[2]++
+				if testPW[2] > 122 {
+					testPW[2] = 97
+					testPW[3]++
+					if testPW[3] > 122 {
+						testPW[3] = 97
+						testPW[4]++
+						if testPW[4] > 122 {
+							testPW[4] = 97
+							testPW[5]++
+							if testPW[5] > 122 {
+								fmt.Printf("Failed to find password\n")
+								out <- []byte("ERROR")
+							}
+						}
+					}
+				}
+			}
+		}
+
+	}
+}
+
+//C40BreakRSA encrypts the given message three times using three
+//randomly-generated RSA public keys, then breaks the encryption
+func C40BreakRSA(msg []byte) {
+	e1, _, n1 := pubkey.GenerateRSAKeyPair(64)
+	e2, _, n2 := pubkey.GenerateRSAKeyPair(64)
+	e3, _, n3 := pubkey.GenerateRSAKeyPair(64)
+
+	encrypted1 := pubkey.RSAEncrypt(msg, e1, n1)
+	encrypted2 := pubkey.RSAEncrypt(msg, e2, n2)
+	encrypted3 := pubkey.RSAEncrypt(msg, e3, n3)
+
+	decryptedH, _ := RSABroadcastAttack([][]byte{encrypted1, encrypted2, encrypted3}, []*big.Int{n1, n2, n3})
+
+	fmt.Printf("Original bytes: %X\n", msg)
+	fmt.Printf("Dcrypted bytes: %X\n", decryptedH) //Typo is intentional for alignment
+	fmt.Printf("Original message %v\n", string(msg))
+	fmt.Printf("Dcrypted message %v\n", string(decryptedH))
+
+}
+
+//RSABroadcastAttack recovers a message encrypted under e different
+//RSA public keys [e, n_i], all with the same small exponent e, by
+//combining the ciphertexts with the Chinese remainder theorem and
+//taking an e-th root. The exponent is taken to be the number of
+//ciphertexts. Returns a non-nil error if the number of ciphertexts
+//and moduli differ, or if the moduli are not pairwise coprime.
+func RSABroadcastAttack(ctexts [][]byte, ns []*big.Int) ([]byte, error) {
+	if len(ctexts) != len(ns) {
+		return nil, fmt.Errorf("Need one modulus per ciphertext")
+	}
+	totalMod := big.NewInt(1)
+	for _, n := range ns {
+		totalMod.Mul(totalMod, n)
+	}
+
+	tot := big.NewInt(0)
+	for i, c := range ctexts {
+		ms := big.NewInt(0).Div(totalMod, ns[i])
+		inv := big.NewInt(0).ModInverse(ms, ns[i])
+		if inv == nil {
+			return nil, fmt.Errorf("Moduli are not pairwise coprime")
+		}
+		res := big.NewInt(0).SetBytes(c)
+		res.Mul(res, ms)
+		res.Mul(res, inv)
+		tot.Add(tot, res)
+	}
+	tot.Mod(tot, totalMod)
+
+	return mathutil.NRoot(tot, len(ctexts)).Bytes(), nil
+}
@@ -1,12 +1,16 @@
-package main
+package attacks
 
 import (
-	"bytes"
 	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/asn1"
 	"fmt"
 	"math/big"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/mathutil"
+	"github.com/alanese/cryptopals/oracles"
+	"github.com/alanese/cryptopals/pubkey"
 )
 
 //C41Recovery implements an unpadded message recovery attack
@@ -19,7 +23,7 @@ func C41Recovery(ctext []byte, e, d, n *big.Int) {
 	cprime.Mul(cprime, c)
 	cprime.Mod(cprime, n)
 
-	pprime := big.NewInt(0).SetBytes(RSADecrypt(cprime.Bytes(), d, n))
+	pprime := big.NewInt(0).SetBytes(pubkey.RSADecrypt(cprime.Bytes(), d, n))
 
 	sInv := big.NewInt(0).ModInverse(s, n)
 	p := big.NewInt(0).Mul(pprime, sInv)
@@ -30,73 +34,13 @@ func C41Recovery(ctext []byte, e, d, n *big.Int) {
 
 }
 
-//C42CheckHash determines whether the digest in digestInfo is the SHA-256 digest
-//produced by msg
-func C42CheckHash(digestinfo RSASignatureDigestInfo, msg []byte) bool {
-	verifyHash := digestinfo.Digest
-	targetHash := sha256.Sum256(msg)
-	return bytes.Equal(verifyHash, targetHash[:])
-}
-
-//C42CheckRSASignature determines (incorrectly) if rsaSignature is a properly
-//padded and encrypted RSA SHA-256 signature for msg; the function does not
-//properly ensure the padding is long enough, enabling Bleichenbacher's attack
-//for sufficiently long n
-func C42CheckRSASignature(msg []byte, rsaSignature []byte, e, n *big.Int) bool {
-	sig := RSAEncryptPad(rsaSignature, e, n)
-
-	notPadding := 0
-	padding00 := 1
-	padding0001 := 2
-	paddingFF := 3
-	state := 0
-	i := 0
-	//I feel like there's a better way to do this than essentially implementing a DFA
-	for i < len(sig) {
-		switch state {
-		case notPadding:
-			if sig[i] == 0x00 {
-				state = padding00
-			}
-		case padding00:
-			if sig[i] == 0x01 {
-				state = padding0001
-			} else {
-				state = notPadding
-			}
-		case padding0001:
-			if sig[i] == 0x00 {
-				digestInfo := UnmarshalDigestInfo(sig[i+1:])
-				return C42CheckHash(digestInfo, msg) //deliberately fail to check right-justification
-			} else if sig[i] == 0xFF {
-				state = paddingFF
-			} else {
-				state = notPadding
-			}
-		case paddingFF:
-			if sig[i] == 0x00 {
-				digestInfo := UnmarshalDigestInfo(sig[i+1:])
-				return C42CheckHash(digestInfo, msg) //deliberately fail to check right-justification
-			} else if sig[i] != 0xFF {
-				state = notPadding
-			}
-		default:
-			panic("Unexpected state error") //this shouldn't happen
-
-		}
-		i++
-	}
-	return false
-
-}
-
 //C42ForgeSignature forges an e=3 RSA signature for the given message
 //via a flawed padding check in the verifier. This will fail if n isn't
 //at least roughly three times the length of the encoded ASN data (47 bytes)
 func C42ForgeSignature(msg []byte, n *big.Int) []byte {
 	dataLength := len(n.Bytes())
 	digest := sha256.Sum256(msg)
-	asnData, _ := asn1.Marshal(RSASignatureDigestInfo{sha256OID, digest[:]})
+	asnData, _ := asn1.Marshal(pubkey.RSASignatureDigestInfo{DigestAlgorithm: pubkey.SHA256OID, Digest: digest[:]})
 	fmt.Println(len(asnData))
 
 	padding := make([]byte, dataLength/3-len(asnData))
@@ -106,10 +50,10 @@ func C42ForgeSignature(msg []byte, n *big.Int) []byte {
 	}
 	dHead := append(padding, asnData...)
 	garbageLength := dataLength - len(dHead)
-	garbage := GenerateRandomByteSlice(garbageLength)
+	garbage := bytesutil.GenerateRandomByteSlice(garbageLength)
 	forgedD := append(dHead, garbage...)
 	forgedDNum := big.NewInt(0).SetBytes(forgedD)
-	forgedSigNum := NRoot(forgedDNum, 3)
+	forgedSigNum := mathutil.NRoot(forgedDNum, 3)
 	forgedSig := forgedSigNum.Bytes()
 	return forgedSig
 }
@@ -204,9 +148,9 @@ func C44FindCommonK(md1, md2 []byte, s1, s2, q *big.Int) *big.Int {
 }
 
 //C44FindKey finds the private key for challenge 44
-func C44FindKey(fname string, pubkey, p, q, g *big.Int) *big.Int {
+func C44FindKey(fname string, pubKey, p, q, g *big.Int) *big.Int {
 	//Parse file
-	lines, _ := LinesFromFile(fname)
+	lines, _ := bytesutil.LinesFromFile(fname)
 	sigs := make([]C44DSASHA1Sig, 0)
 	for i := 0; i < len(lines); i += 4 {
 		msg := lines[i][5:]
@@ -227,7 +171,7 @@ func C44FindKey(fname string, pubkey, p, q, g *big.Int) *big.Int {
 			}
 			fmt.Printf("Candidate K: %X\n", candidateK)
 			candidateX := RecoverDSAPrivateKey(sigs[i].Digest, sigs[i].R, sigs[i].S, candidateK, q)
-			candidateR, candidateS, err := DSASignSHA1Forcek(sigs[i].Msg, candidateK, candidateX, p, q, g)
+			candidateR, candidateS, err := pubkey.DSASignSHA1Forcek(sigs[i].Msg, candidateK, candidateX, p, q, g)
 			if err != nil {
 				fmt.Println("Error signing with candidate X\n-----")
 				continue
@@ -256,17 +200,10 @@ func C45MagicSignature(pubKey, p, q *big.Int) (r, s *big.Int) {
 	return
 }
 
-//C46RSAParityOracle decrypts ciphertext with the RSA private
-//key [d, n] and returns true iff the resulting plaintext is odd
-func C46RSAParityOracle(ciphertext, d, n *big.Int) bool {
-	plaintext := big.NewInt(0).Exp(ciphertext, d, n)
-	return plaintext.Bit(0) != 0
-}
-
 //C46RSAParityAttack decrypts a ciphertext given a public RSA key [e,n]
-//using a parity oracle. The private key d is passed in only to be
-//passed to the oracle
-func C46RSAParityAttack(ciphertext []byte, e, d, n *big.Int) []byte {
+//using a parity oracle. The verbose parameter determines whether the
+//narrowing bounds are printed to console.
+func C46RSAParityAttack(ciphertext []byte, e, n *big.Int, oracle oracles.RSAParityOracle, verbose bool) []byte {
 	two := big.NewInt(2)
 	one := big.NewInt(1)
 	cTextDouble := big.NewInt(0).Exp(two, e, n)
@@ -282,10 +219,12 @@ func C46RSAParityAttack(ciphertext []byte, e, d, n *big.Int) []byte {
 		testCText.Mul(testCText, cTextDouble)
 		testCText.Mod(testCText, n)
 
-		fmt.Printf("Upper: %X\nLower: %X\n-------\n", upperBd, lowerBd)
+		if verbose {
+			fmt.Printf("Upper: %X\nLower: %X\n-------\n", upperBd, lowerBd)
+		}
 		upperBdDivided.Mul(upperBdDivided, two)
 		lowerBdDivided.Mul(lowerBdDivided, two)
-		if C46RSAParityOracle(testCText, d, n) {
+		if oracle.IsOdd(testCText) {
 			lowerBdDivided.Add(lowerBdDivided, one)
 		} else {
 			upperBdDivided.Sub(upperBdDivided, one)
@@ -1,12 +1,13 @@
-package main
+package attacks
 
 import (
 	"bytes"
 	"fmt"
-	"math/rand"
-	"strconv"
+	"time"
 
-	"golang.org/x/crypto/twofish"
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/hashes"
+	"github.com/alanese/cryptopals/oracles"
 )
 
 //C49ForgeMessage forges a message transmitting
@@ -21,9 +22,9 @@ func C49ForgeMessage(origMessage, origIV []byte) ([]byte, []byte) {
 
 	myAcct := []byte("563")
 	origTo := origMessage[12:15]
-	flipper, _ := XorBufs(myAcct, origTo)
+	flipper, _ := bytesutil.XorBufs(myAcct, origTo)
 
-	newIvChunk, _ := XorBufs(flipper, origIV[12:15])
+	newIvChunk, _ := bytesutil.XorBufs(flipper, origIV[12:15])
 	newIV[12] = newIvChunk[0]
 	newIV[13] = newIvChunk[1]
 	newIV[14] = newIvChunk[2]
@@ -45,7 +46,7 @@ func C50ForgeMsg() []byte {
 	origMsg := []byte("alert('MZA who was that?');\n")
 	iv := make([]byte, 16)
 	key := []byte("YELLOW SUBMARINE")
-	mac := AESCBCMAC(origMsg, iv, key)
+	mac := hashes.AESCBCMAC(origMsg, iv, key)
 	fmt.Printf("%X\n", mac)
 
 	var newMessage []byte
@@ -56,59 +57,27 @@ func C50ForgeMsg() []byte {
 	for {
 		fmt.Printf("Testing %X\n", rPad)
 		testMsg := append(newMsgFront, rPad...)
-		testMAC, _ := AESCBCMACNoPad(testMsg, iv, key)
-		mangledBlock, _ := XorBufs(origFirstBlock, testMAC)
-		if AllBytesPrintable(mangledBlock) {
+		testMAC, _ := hashes.AESCBCMACNoPad(testMsg, iv, key)
+		mangledBlock, _ := bytesutil.XorBufs(origFirstBlock, testMAC)
+		if bytesutil.AllBytesPrintable(mangledBlock) {
 			newMessage = append(newMsgFront, rPad...)
 			newMessage = append(newMessage, mangledBlock...)
 			newMessage = append(newMessage, origRemainder...)
 			break
 		}
-		IncrementPrintableBytes(rPad)
+		bytesutil.IncrementPrintableBytes(rPad)
 	}
 
-	newMAC := AESCBCMAC(newMessage, iv, key)
+	newMAC := hashes.AESCBCMAC(newMessage, iv, key)
 	fmt.Printf("%X\n", newMAC)
 	fmt.Println(string(newMessage))
 	return newMessage
 }
 
-//C51FormatRequest formats a request per challenge 51
-func C51FormatRequest(p []byte) []byte {
-	return []byte("POST / HTTP/1.1\n" +
-		"Host: hapless.com\n" +
-		"Cookie: sessionid=TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=\n" +
-		"Content-length: " + strconv.Itoa(len(p)) + "\n" +
-		string(p))
-}
-
-//C51OracleStream implements the compression oracle in challenge 51
-//Encrypts with a MT19937 stream cipher because it's a stream
-//cipher i have implemented
-func C51OracleStream(p []byte) int {
-	key := rand.Uint32()
-	rq := C51FormatRequest(p)
-	compressed := CompressDEFLATE(rq)
-	encrypted := EncryptMT19937Stream(compressed, key)
-	return len(encrypted)
-}
-
-//C51OracleCBC implements a CBC version of the compression
-//oracle in challenge 51
-func C51OracleCBC(p []byte) int {
-	key := GenerateRandomByteSlice(16)
-	iv := GenerateRandomByteSlice(16)
-	rq := C51FormatRequest(p)
-	compressed := CompressDEFLATE(rq)
-	padded := PKCSPad(compressed, 16)
-	encrypted := EncryptAESCBC(padded, key, iv)
-	return len(encrypted)
-}
-
 //C51FindCookie uses a compression ratio attack to find
 //a secret cookie in a request compressed with DEFLATE
-//and encrypted with a stream cipher (MT19937, in this case)
-func C51FindCookie() []byte {
+//and encrypted with a stream cipher, such as C51OracleStream
+func C51FindCookie(oracle oracles.CompressionOracle) []byte {
 	base64Bytes := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=\n")
 	knownBytes := []byte("sessionid=")
 	for {
@@ -121,7 +90,7 @@ func C51FindCookie() []byte {
 				testBytes = append(testBytes, base64Bytes[i])
 			}
 
-			testLen := C51OracleStream(testBytes)
+			testLen := oracle.Length(testBytes)
 			if testLen < bestLength || i == 0 {
 				bestLength = testLen
 				bestByte = base64Bytes[i]
@@ -135,41 +104,15 @@ func C51FindCookie() []byte {
 	}
 }
 
-//C52MD implements a simplified MD iterated hash using AES-ECB
-//with a digest size of 16 bits
-func C52MD(M, H []byte) []byte {
-	key := PadLeft(H, 0x00, 16)
-	msg := M
-	for i := 0; i*16 < len(msg); i++ {
-		longKey := EncryptAESECB(msg[16*i:16*(i+1)], key)
-		key = PadLeft(longKey[14:], 0x00, 16)
-	}
-	return key[14:]
-}
-
-//C52TwofishMD implements a simplified MD iterated hash using
-//Twofish with a digest size of 16 bits
-func C52TwofishMD(M, H []byte) []byte {
-	key := PadLeft(H, 0x00, 16)
-	block := make([]byte, 16)
-	copy(block, M[:16])
-	for i := 0; i*16 < len(M); i++ {
-		c, _ := twofish.NewCipher(key)
-		c.Encrypt(block, M[i*16:(i+1)*16])
-		key = PadLeft(block[14:], 0x00, 16)
-	}
-	return key[14:]
-}
-
 //C52GenerateCollision finds two 16-byte slices which produce the
 //same hash under C52MD with the given initial state (which should be
 //two bytes)
 func C52GenerateCollision(initState []byte) ([]byte, []byte) {
 	for {
-		i1 := GenerateRandomByteSlice(16)
-		i2 := GenerateRandomByteSlice(16)
-		h1 := C52MD(i1, initState)
-		h2 := C52MD(i2, initState)
+		i1 := bytesutil.GenerateRandomByteSlice(16)
+		i2 := bytesutil.GenerateRandomByteSlice(16)
+		h1 := hashes.C52MD(i1, initState)
+		h2 := hashes.C52MD(i2, initState)
 		if bytes.Equal(h1, h2) {
 			return i1, i2
 		}
@@ -183,7 +126,7 @@ func C52GenerateManyCollisions(initState []byte, n int) [][]byte {
 	pairs := make([][][]byte, n)
 	for i := 0; i < n; i++ {
 		i1, i2 := C52GenerateCollision(state)
-		state = C52MD(i1, state)
+		state = hashes.C52MD(i1, state)
 		pairs[i] = [][]byte{i1, i2}
 	}
 	fmt.Printf("%x\n", pairs)
@@ -202,11 +145,11 @@ func C52GenerateManyCollisions(initState []byte, n int) [][]byte {
 //C53GenerateCollisionPair generates a one-block message and a message of the given
 //length in blocks which collide under C52 with the given initial state
 func C53GenerateCollisionPair(initState []byte, longBlocks int) ([]byte, []byte) {
-	longMsg := GenerateRandomByteSlice(longBlocks * 16)
-	longHash := C52MD(longMsg, initState)
+	longMsg := bytesutil.GenerateRandomByteSlice(longBlocks * 16)
+	longHash := hashes.C52MD(longMsg, initState)
 	for {
-		shortMsg := GenerateRandomByteSlice(16)
-		shortHash := C52MD(shortMsg, initState)
+		shortMsg := bytesutil.GenerateRandomByteSlice(16)
+		shortHash := hashes.C52MD(shortMsg, initState)
 		if bytes.Equal(shortHash, longHash) {
 			return shortMsg, longMsg
 		}
@@ -223,7 +166,7 @@ func C53GenerateExpandableMessage(initState []byte, k int) (shortMsgs, longMsgs
 		short, long := C53GenerateCollisionPair(state, (1<<(k-i-1))+1)
 		shortMsgs[i] = short
 		longMsgs[i] = long
-		state = C52MD(short, state)
+		state = hashes.C52MD(short, state)
 	}
 	return
 
@@ -236,7 +179,7 @@ func C53ForgeMessage(msg, initState []byte, k int) []byte {
 	intermediateStates := make(map[string]int)
 	state := initState
 	for i := 0; i*16 < len(msg); i++ {
-		state = C52MD(msg[i*16:(i+1)*16], state)
+		state = hashes.C52MD(msg[i*16:(i+1)*16], state)
 		intermediateStates[fmt.Sprintf("%x", state)] = i + 1
 	}
 
@@ -245,8 +188,8 @@ func C53ForgeMessage(msg, initState []byte, k int) []byte {
 	var bridgeIndex int
 	ok := false
 	for !ok || bridgeIndex < k {
-		bridge = GenerateRandomByteSlice(16)
-		bridgeState := C52MD(bridge, expandableState)
+		bridge = bytesutil.GenerateRandomByteSlice(16)
+		bridgeState := hashes.C52MD(bridge, expandableState)
 		bridgeIndex, ok = intermediateStates[fmt.Sprintf("%x", bridgeState)]
 	}
 
@@ -279,7 +222,7 @@ type C54CollisionTreeNode struct {
 func C54CollisionTree(k int) (leaves []*C54CollisionTreeNode) {
 	leaves = make([]*C54CollisionTreeNode, 0)
 	for i := 0; i < 1<<k; i++ {
-		state := GenerateRandomByteSlice(2)
+		state := bytesutil.GenerateRandomByteSlice(2)
 		tmp := C54CollisionTreeNode{state, nil, nil}
 		leaves = append(leaves, &tmp)
 	}
@@ -318,10 +261,10 @@ func C54NodeBuildMessage(node *C54CollisionTreeNode) (msg []byte) {
 //under C52MD from the given initial states
 func C54GenerateCollision(initState1, initState2 []byte) (msg1, msg2, finalState []byte) {
 	for {
-		msg1 = GenerateRandomByteSlice(16)
-		msg2 = GenerateRandomByteSlice(16)
-		finalState = C52MD(msg1, initState1)
-		finalState2 := C52MD(msg2, initState2)
+		msg1 = bytesutil.GenerateRandomByteSlice(16)
+		msg2 = bytesutil.GenerateRandomByteSlice(16)
+		finalState = hashes.C52MD(msg1, initState1)
+		finalState2 := hashes.C52MD(msg2, initState2)
 		if bytes.Equal(finalState, finalState2) {
 			return
 		}
@@ -331,11 +274,11 @@ func C54GenerateCollision(initState1, initState2 []byte) (msg1, msg2, finalState
 //C54GeneratePreimage generates a message with the given prefix that, under the given
 //initial state, hashes (via C52MD) to the state at the root of the collision tree
 func C54GeneratePreimage(msg, initState []byte, leaves []*C54CollisionTreeNode) []byte {
-	finalState := C52MD(msg, initState)
+	finalState := hashes.C52MD(msg, initState)
 
 	for {
-		bridge := GenerateRandomByteSlice(16)
-		bridgeState := C52MD(bridge, finalState)
+		bridge := bytesutil.GenerateRandomByteSlice(16)
+		bridgeState := hashes.C52MD(bridge, finalState)
 		for _, v := range leaves {
 			if bytes.Equal(v.State, bridgeState) {
 				preimage := append(msg, bridge...)
@@ -346,3 +289,40 @@ func C54GeneratePreimage(msg, initState []byte, leaves []*C54CollisionTreeNode)
 
 	}
 }
+
+//C56GuessByte attempts to guess byte n of the secret cookie
+func C56GuessByte(n, runs int) byte {
+	prefix := make([]byte, 31-n)
+
+	counter := make([]int, 256)
+
+	for i := 0; i < runs; i++ {
+		ctext := oracles.C56Oracle(prefix)
+		counter[int(ctext[31])]++
+	}
+
+	maxCount := -1
+	bestByte := byte(0)
+	for i, v := range counter {
+		if v > maxCount {
+			maxCount = v
+			bestByte = byte(i)
+		}
+	}
+
+	return bestByte ^ 0xe0 //byte 31 of keystream biased towards 224
+}
+
+//C56GuessCookie guesses the secret cookie in challenge 56
+func C56GuessCookie() []byte {
+	guessedBytes := make([]byte, 30)
+	for i := 0; i < 30; i++ {
+		startTime := time.Now()
+		guessedBytes[i] = C56GuessByte(i, 1<<24)
+		endTime := time.Now()
+		elapsedTime := endTime.Unix() - startTime.Unix()
+		fmt.Printf("Byte %v elapsed time %vs\n", i, elapsedTime)
+		fmt.Printf("%x\n%v\n", guessedBytes, string(guessedBytes))
+	}
+	return guessedBytes
+}
@@ -0,0 +1,284 @@
+//This file contains a statistically robust timing attack on an
+//early-exit MAC comparison, for leaks far smaller than the noise
+
+package attacks
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//TimingStatistic selects how the timing samples for a candidate
+//byte are summarised
+type TimingStatistic int
+
+const (
+	//Median summarises samples by their median
+	Median TimingStatistic = iota
+	//TrimmedMean summarises samples by their mean after discarding
+	//a fraction of the smallest and largest
+	TrimmedMean
+)
+
+//TimingAttackConfig holds the tuning parameters for BreakHMACTiming
+type TimingAttackConfig struct {
+	MACLength     int             //length of the MAC in bytes
+	Samples       int             //samples per candidate in the first round; doubles each round
+	MaxRounds     int             //rounds of sampling and pruning per byte
+	Statistic     TimingStatistic //how samples are summarised
+	TrimFraction  float64         //fraction trimmed from each end for TrimmedMean
+	Cutoff        float64         //candidates this many standard errors behind the leader are pruned
+	MinConfidence float64         //bytes guessed with less confidence trigger backtracking
+	MaxBacktracks int             //give up after this many backtracks
+	MaxEffort     int             //backtracking multiplies a byte's samples by at most this (1 if zero)
+}
+
+//DefaultTimingAttackConfig is a configuration for a 20-byte MAC
+//which recovers per-byte delays of tens of microseconds under a
+//few hundred microseconds of jitter
+var DefaultTimingAttackConfig = TimingAttackConfig{
+	MACLength:     20,
+	Samples:       16,
+	MaxRounds:     9,
+	Statistic:     TrimmedMean,
+	TrimFraction:  0.1,
+	Cutoff:        3,
+	MinConfidence: 0.99,
+	MaxBacktracks: 20,
+	MaxEffort:     16,
+}
+
+//TimingAttackResult is the outcome of BreakHMACTiming
+type TimingAttackResult struct {
+	MAC        []byte
+	Confidence []float64 //per byte; the last byte is confirmed by the oracle
+	Queries    int
+	Backtracks int
+}
+
+//timingCandidate holds the samples gathered for one candidate byte
+type timingCandidate struct {
+	value   byte
+	samples []float64
+	stat    float64
+	stdErr  float64
+}
+
+//summarise computes the candidate's statistic and its standard error
+func (c *timingCandidate) summarise(stat TimingStatistic, trim float64) {
+	s := append([]float64{}, c.samples...)
+	sort.Float64s(s)
+	n := float64(len(s))
+	if stat == Median {
+		c.stat = quantile(s, 0.5)
+		//scaled median absolute deviation estimates the standard deviation
+		dev := make([]float64, len(s))
+		for i, v := range s {
+			dev[i] = math.Abs(v - c.stat)
+		}
+		sort.Float64s(dev)
+		sigma := 1.4826 * quantile(dev, 0.5)
+		c.stdErr = 1.2533 * sigma / math.Sqrt(n)
+		return
+	}
+
+	//winsorized variance gives the standard error of the trimmed mean
+	g := int(trim * n)
+	trimmed := s[g : len(s)-g]
+	sum := 0.0
+	for _, v := range trimmed {
+		sum += v
+	}
+	c.stat = sum / float64(len(trimmed))
+	lo, hi := trimmed[0], trimmed[len(trimmed)-1]
+	wMean := 0.0
+	for _, v := range s {
+		wMean += math.Min(math.Max(v, lo), hi)
+	}
+	wMean /= n
+	wVar := 0.0
+	for _, v := range s {
+		d := math.Min(math.Max(v, lo), hi) - wMean
+		wVar += d * d
+	}
+	wVar /= n - 1
+	c.stdErr = math.Sqrt(wVar) / ((1 - 2*trim) * math.Sqrt(n))
+}
+
+//quantile returns the q-quantile of sorted, interpolating linearly
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i]*(1-frac) + sorted[i+1]*frac
+}
+
+//normalCDF is the standard normal cumulative distribution function
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
+
+//BreakHMACTiming finds the MAC for msg expected by a timing oracle
+//which compares MACs byte by byte with early exit. Each byte is found
+//by timing every candidate many times, interleaving the queries so
+//drift affects all candidates alike, and summarising with a median or
+//trimmed mean. Candidates falling cfg.Cutoff standard errors behind
+//the leader are pruned, and the survivors resampled with twice as many
+//queries, until one remains or cfg.MaxRounds is reached. The confidence
+//in a byte is the probability the leader is truly slower than the
+//runner-up; a byte guessed with too little confidence suggests an
+//earlier byte is wrong, so the attack backtracks one byte, doubling
+//the sampling effort for that byte and the one it gave up on, up to
+//cfg.MaxEffort times the first round's. The last byte is found by
+//checking which candidate the oracle accepts. Returns a non-nil error
+//if the configuration is invalid or the attack runs out of
+//backtracks.
+func BreakHMACTiming(oracle oracles.TimingOracle, msg []byte, cfg TimingAttackConfig, verbose bool) (TimingAttackResult, error) {
+	if err := checkTimingConfig(cfg); err != nil {
+		return TimingAttackResult{}, err
+	}
+	res := TimingAttackResult{
+		MAC:        make([]byte, cfg.MACLength),
+		Confidence: make([]float64, cfg.MACLength),
+	}
+	//effort[i] multiplies the samples taken for byte i
+	effort := make([]int, cfg.MACLength)
+	for i := range effort {
+		effort[i] = 1
+	}
+	maxEffort := max(cfg.MaxEffort, 1)
+	i := 0
+	for i < cfg.MACLength {
+		var b byte
+		var conf float64
+		if i == cfg.MACLength-1 {
+			b, conf = lastByteByCheck(oracle, msg, res.MAC, &res.Queries)
+		} else {
+			b, conf = guessByteByTiming(oracle, msg, res.MAC, i, cfg, effort[i], &res.Queries)
+		}
+		if verbose {
+			fmt.Printf("Byte %v: %02x, confidence %.4f (%v queries so far)\n", i, b, conf, res.Queries)
+		}
+
+		if conf < cfg.MinConfidence {
+			if res.Backtracks >= cfg.MaxBacktracks {
+				return res, errors.New("Too many backtracks; timing leak too small for the configured sampling")
+			}
+			res.Backtracks++
+			effort[i] = min(2*effort[i], maxEffort)
+			if i > 0 {
+				i--
+				effort[i] = min(2*effort[i], maxEffort)
+			}
+			if verbose {
+				fmt.Printf("Low confidence; backtracking to byte %v with effort x%v\n", i, effort[i])
+			}
+			continue
+		}
+		res.MAC[i] = b
+		res.Confidence[i] = conf
+		i++
+	}
+	return res, nil
+}
+
+//checkTimingConfig returns a non-nil error if cfg cannot be used
+func checkTimingConfig(cfg TimingAttackConfig) error {
+	switch {
+	case cfg.MACLength < 1:
+		return errors.New("MAC length must be positive")
+	case cfg.Samples < 1:
+		return errors.New("Samples must be positive")
+	case cfg.MaxRounds < 1:
+		return errors.New("MaxRounds must be positive")
+	case cfg.Statistic == TrimmedMean && (cfg.TrimFraction < 0 || cfg.TrimFraction >= 0.5):
+		return errors.New("TrimFraction must be at least 0 and less than 0.5")
+	}
+	return nil
+}
+
+//guessByteByTiming guesses byte pos of the MAC given the bytes before
+//it, returning the guess and the confidence in it
+func guessByteByTiming(oracle oracles.TimingOracle, msg, known []byte, pos int, cfg TimingAttackConfig, effort int, queries *int) (byte, float64) {
+	cands := make([]*timingCandidate, 256)
+	for v := range cands {
+		cands[v] = &timingCandidate{value: byte(v)}
+	}
+	alive := append([]*timingCandidate{}, cands...)
+	mac := make([]byte, len(known))
+	copy(mac, known[:pos])
+
+	//runnerUp is the strongest candidate pruned in the latest round
+	var runnerUp *timingCandidate
+	samples := cfg.Samples * effort
+	for round := 0; round < cfg.MaxRounds && len(alive) > 1; round++ {
+		for s := 0; s < samples; s++ {
+			//rotate the query order so no candidate is always first
+			for k := range alive {
+				c := alive[(k+s)%len(alive)]
+				mac[pos] = c.value
+				_, t := oracle.TimedCheck(msg, mac)
+				c.samples = append(c.samples, float64(t))
+				*queries++
+			}
+		}
+		for _, c := range alive {
+			c.summarise(cfg.Statistic, cfg.TrimFraction)
+		}
+
+		leader := alive[0]
+		for _, c := range alive {
+			if c.stat > leader.stat {
+				leader = c
+			}
+		}
+		survivors := alive[:0]
+		runnerUp = nil
+		for _, c := range alive {
+			gap := (leader.stat - c.stat) / math.Hypot(leader.stdErr, c.stdErr)
+			if c == leader || !(gap > cfg.Cutoff) {
+				survivors = append(survivors, c)
+			} else if runnerUp == nil || c.stat > runnerUp.stat {
+				runnerUp = c
+			}
+		}
+		alive = survivors
+		samples *= 2
+	}
+
+	//the confidence compares the two best survivors, whose statistics
+	//are current; a lone survivor is compared with the runner-up it
+	//was measured against when the rest were pruned
+	sort.Slice(alive, func(a, b int) bool { return alive[a].stat > alive[b].stat })
+	best, second := alive[0], runnerUp
+	if len(alive) > 1 {
+		second = alive[1]
+	}
+	z := (best.stat - second.stat) / math.Hypot(best.stdErr, second.stdErr)
+	if math.IsNaN(z) {
+		return best.value, 0
+	}
+	return best.value, normalCDF(z)
+}
+
+//lastByteByCheck finds the last byte of the MAC by trying every
+//value, returning confidence 1 if one is accepted and 0 if none are
+func lastByteByCheck(oracle oracles.TimingOracle, msg, known []byte, queries *int) (byte, float64) {
+	mac := append([]byte{}, known...)
+	for v := 0; v < 256; v++ {
+		mac[len(mac)-1] = byte(v)
+		ok, _ := oracle.TimedCheck(msg, mac)
+		*queries++
+		if ok {
+			return byte(v), 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,448 @@
+//This file contains an auditor for password-reset style tokens,
+//testing whether a batch of them came from a predictable generator
+
+package attacks
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/alanese/cryptopals/prng"
+)
+
+//TokenScheme is a way of generating tokens which TokenAudit tests for
+type TokenScheme int
+
+const (
+	//TokenMT19937 is one MT19937 seeded from the clock, each token
+	//taking whole outputs, least significant byte first, as
+	//EncryptMT19937Stream uses them
+	TokenMT19937 TokenScheme = iota
+	//TokenMT19937PerToken is a fresh MT19937 seeded from the clock for
+	//each token, as in challenge 24
+	TokenMT19937PerToken
+	//TokenMathRand is one Go math/rand source seeded from the clock,
+	//each token read from it with Read
+	TokenMathRand
+	//TokenCounterXor is a big-endian counter, one more for each token,
+	//XORed with a fixed secret
+	TokenCounterXor
+)
+
+//String returns the scheme's name
+func (s TokenScheme) String() string {
+	switch s {
+	case TokenMT19937:
+		return "mt19937"
+	case TokenMT19937PerToken:
+		return "mt19937 per token"
+	case TokenMathRand:
+		return "math/rand"
+	case TokenCounterXor:
+		return "counter xor secret"
+	}
+	return "unknown"
+}
+
+//TokenAudit tests a batch of tokens, in the order they were issued,
+//against each TokenScheme. If Plaintext is set, each token is that
+//known plaintext (and perhaps more) encrypted under the scheme's output
+//as a keystream, as in challenge 24; otherwise the tokens are the
+//output itself. The clock-seeded schemes search seeds from From to To
+//in Units since the Unix epoch (time.Second if zero), allowing
+//MaxOffset unseen outputs before the first token; with no window they
+//are only tested by cloning the generator from its outputs. Predict
+//is how many following tokens to predict.
+type TokenAudit struct {
+	Tokens    [][]byte
+	Plaintext []byte
+	From, To  time.Time
+	Unit      time.Duration
+	MaxOffset int
+	Predict   int
+}
+
+//TokenFinding is a scheme which fits the tokens. Seeds holds the seed
+//found, or one per token for TokenMT19937PerToken, and Offset the
+//outputs before the first token. Secret and Counter are the secret and
+//first counter value for TokenCounterXor. Next holds the predicted
+//following tokens, each made as the system would for the same
+//plaintext as the last token. Note describes any assumption made.
+type TokenFinding struct {
+	Scheme  TokenScheme
+	Seeds   []int64
+	Offset  int
+	Secret  []byte
+	Counter *big.Int
+	Next    [][]byte
+	Note    string
+}
+
+//tokenKeystream returns the keystream bytes a token gives away and
+//which of them are known
+func (a TokenAudit) tokenKeystream(token []byte) ([]byte, []bool) {
+	ks := append([]byte{}, token...)
+	known := make([]bool, len(token))
+	for i := range ks {
+		if a.Plaintext == nil {
+			known[i] = true
+		} else if i < len(a.Plaintext) {
+			ks[i] ^= a.Plaintext[i]
+			known[i] = true
+		}
+	}
+	return ks, known
+}
+
+//predict returns the next tokens from keystreams for them, with the
+//plaintext of the last token, found from its keystream
+func (a TokenAudit) predict(lastKeystream []byte, next [][]byte) [][]byte {
+	last := a.Tokens[len(a.Tokens)-1]
+	tokens := make([][]byte, len(next))
+	for i, ks := range next {
+		tokens[i] = make([]byte, len(last))
+		for j := range last {
+			tokens[i][j] = last[j] ^ lastKeystream[j] ^ ks[j]
+		}
+	}
+	return tokens
+}
+
+//observe adds known keystream bytes to observations of outputs of
+//width bytes, placing byte j of the keystream at stream byte start+j
+func observe(obs []SeedObservation, ks []byte, known []bool, start, width int) []SeedObservation {
+	for j, b := range ks {
+		if !known[j] {
+			continue
+		}
+		pos := start + j
+		for len(obs) <= pos/width {
+			obs = append(obs, SeedObservation{})
+		}
+		shift := 8 * (pos % width)
+		obs[pos/width].Mask |= 0xFF << shift
+		obs[pos/width].Value |= uint64(b) << shift
+	}
+	return obs
+}
+
+//seeds returns the seed range of the clock window
+func (a TokenAudit) seeds() (int64, int64) {
+	unit := a.Unit
+	if unit == 0 {
+		unit = time.Second
+	}
+	return TimeSeeds(a.From, a.To, unit)
+}
+
+//Run tests the tokens against every scheme, returning a finding for
+//each which fits. Returns a non-nil error if there are no tokens.
+func (a TokenAudit) Run() ([]TokenFinding, error) {
+	if len(a.Tokens) == 0 {
+		return nil, errors.New("No tokens to audit")
+	}
+	findings := []TokenFinding{}
+	for _, test := range []func() (TokenFinding, bool){a.auditMT, a.auditMTPerToken, a.auditMathRand, a.auditCounterXor} {
+		if f, ok := test(); ok {
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+//mtTokenBytes returns the bytes of the next outputs of t covering n
+//bytes, least significant first
+func mtTokenBytes(t *prng.Twister, n int) []byte {
+	b := make([]byte, 0, n+3)
+	for len(b) < n {
+		v := t.Next()
+		b = append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+	}
+	return b[:n]
+}
+
+//auditMT tests for one clock-seeded MT19937, by seed search in the
+//window, or failing that by cloning it from its outputs
+func (a TokenAudit) auditMT() (TokenFinding, bool) {
+	obs := []SeedObservation{}
+	start, lastStart := 0, 0
+	for _, tok := range a.Tokens {
+		ks, known := a.tokenKeystream(tok)
+		obs = observe(obs, ks, known, start, 4)
+		lastStart = start / 4
+		start += (len(tok) + 3) / 4 * 4
+	}
+	for len(obs) < start/4 {
+		obs = append(obs, SeedObservation{})
+	}
+
+	//t produces output pos next, counting from the first token's
+	f := TokenFinding{Scheme: TokenMT19937}
+	var t *prng.Twister
+	pos := 0
+	if !a.From.IsZero() {
+		from, to := a.seeds()
+		search := SeedSearch{Generator: SeedMT19937, Observed: obs, From: from, To: to, MaxOffset: a.MaxOffset}
+		if matches, err := search.Run(); err == nil && len(matches) > 0 {
+			tw := prng.NewTwister(uint32(matches[0].Seed))
+			for i := 0; i < matches[0].Offset; i++ {
+				tw.Next()
+			}
+			t = &tw
+			f.Seeds, f.Offset = []int64{matches[0].Seed}, matches[0].Offset
+			if len(matches) > 1 {
+				f.Note = fmt.Sprintf("%d seeds fit; using the first", len(matches))
+			}
+		}
+	}
+	if t == nil {
+		t, pos = cloneTwisterFromObservations(obs, lastStart)
+		if t == nil {
+			return f, false
+		}
+		f.Note = "Cloned from the outputs; the seed is unknown"
+	}
+
+	for ; pos < lastStart; pos++ {
+		t.Next()
+	}
+	lastKs := mtTokenBytes(t, len(a.Tokens[len(a.Tokens)-1]))
+	next := make([][]byte, a.Predict)
+	for i := range next {
+		next[i] = mtTokenBytes(t, len(lastKs))
+	}
+	f.Next = a.predict(lastKs, next)
+	return f, true
+}
+
+//cloneTwisterFromObservations clones a twister from its outputs,
+//returning it with the index of the output it produces next, which is
+//at most before. Given 624 consecutive whole outputs starting by then,
+//their untempered words are the state from which the first is output;
+//otherwise its state is recovered from whatever bits are known. Returns
+//nil if neither works.
+func cloneTwisterFromObservations(obs []SeedObservation, before int) (*prng.Twister, int) {
+	run := 0
+	for i, o := range obs {
+		if i-run > before {
+			break
+		}
+		if o.Mask&0xFFFFFFFF != 0xFFFFFFFF {
+			run = 0
+			continue
+		}
+		run++
+		if run < 624 {
+			continue
+		}
+		first := i - 623
+		var state [624]uint32
+		for j := range state {
+			state[j] = TwisterUntemper(uint32(obs[first+j].Value))
+		}
+		var t prng.Twister
+		t.SetState(state, 0)
+		if checkSeedObservations(&t, obs[first:]) {
+			return &t, first
+		}
+		break
+	}
+
+	tobs := []TwisterObservation{}
+	for i, o := range obs {
+		if o.Mask != 0 {
+			tobs = append(tobs, TwisterObservation{i, uint32(o.Mask), uint32(o.Value)})
+		}
+	}
+	t, err := RecoverTwisterState(tobs)
+	if err != nil {
+		return nil, 0
+	}
+	return t, 0
+}
+
+//checkSeedObservations reports whether the outputs of t from its next
+//one match the observations, leaving t as it was
+func checkSeedObservations(t *prng.Twister, obs []SeedObservation) bool {
+	c := t.Clone()
+	for _, o := range obs {
+		if uint64(c.Next())&o.Mask != o.Value&o.Mask {
+			return false
+		}
+	}
+	return true
+}
+
+//auditMTPerToken tests for a fresh clock-seeded MT19937 per token, as
+//in challenge 24, by a seed search for each token
+func (a TokenAudit) auditMTPerToken() (TokenFinding, bool) {
+	if a.From.IsZero() {
+		return TokenFinding{}, false
+	}
+	from, to := a.seeds()
+	f := TokenFinding{Scheme: TokenMT19937PerToken}
+	for _, tok := range a.Tokens {
+		ks, known := a.tokenKeystream(tok)
+		search := SeedSearch{Generator: SeedMT19937, Observed: observe(nil, ks, known, 0, 4), From: from, To: to}
+		matches, err := search.Run()
+		if err != nil || len(matches) == 0 {
+			return f, false
+		}
+		f.Seeds = append(f.Seeds, matches[0].Seed)
+	}
+
+	last := f.Seeds[len(f.Seeds)-1]
+	lastTw := prng.NewTwister(uint32(last))
+	lastKs := mtTokenBytes(&lastTw, len(a.Tokens[len(a.Tokens)-1]))
+	next := make([][]byte, a.Predict)
+	for i := range next {
+		t := prng.NewTwister(uint32(last + int64(i) + 1))
+		next[i] = mtTokenBytes(&t, len(lastKs))
+	}
+	f.Next = a.predict(lastKs, next)
+	f.Note = "Predictions are for tokens issued one clock unit apart after the last"
+	return f, true
+}
+
+//auditMathRand tests for one clock-seeded math/rand source read from
+//with Read, which takes 7 bytes from each Int63, least significant first
+func (a TokenAudit) auditMathRand() (TokenFinding, bool) {
+	if a.From.IsZero() {
+		return TokenFinding{}, false
+	}
+	obs := []SeedObservation{}
+	start := 0
+	for _, tok := range a.Tokens {
+		ks, known := a.tokenKeystream(tok)
+		obs = observe(obs, ks, known, start, 7)
+		start += len(tok)
+	}
+	from, to := a.seeds()
+	search := SeedSearch{Generator: SeedGoMathRand, Observed: obs, From: from, To: to, MaxOffset: a.MaxOffset}
+	matches, err := search.Run()
+	if err != nil || len(matches) == 0 {
+		return TokenFinding{}, false
+	}
+	m := matches[0]
+	f := TokenFinding{Scheme: TokenMathRand, Seeds: []int64{m.Seed}, Offset: m.Offset}
+	if len(matches) > 1 {
+		f.Note = fmt.Sprintf("%d seeds fit; using the first", len(matches))
+	}
+
+	r := rand.New(rand.NewSource(m.Seed))
+	for i := 0; i < m.Offset; i++ {
+		r.Int63()
+	}
+	var lastKs []byte
+	for _, tok := range a.Tokens {
+		lastKs = make([]byte, len(tok))
+		r.Read(lastKs)
+	}
+	next := make([][]byte, a.Predict)
+	for i := range next {
+		next[i] = make([]byte, len(lastKs))
+		r.Read(next[i])
+	}
+	f.Next = a.predict(lastKs, next)
+	return f, true
+}
+
+//auditCounterXor tests for tokens t_i = (c+i) XOR s. Each token XORed
+//with the first is c XOR (c+i), which gives away the low bits of c up
+//to the highest carry; these are found a bit at a time, and the rest of
+//c is taken as zero.
+func (a TokenAudit) auditCounterXor() (TokenFinding, bool) {
+	if len(a.Tokens) < 2 {
+		return TokenFinding{}, false
+	}
+	n := len(a.Tokens[0])
+	first, _ := a.tokenKeystream(a.Tokens[0])
+	diffs := make([]*big.Int, len(a.Tokens))
+	width := 0
+	for i, tok := range a.Tokens {
+		ks, known := a.tokenKeystream(tok)
+		if len(tok) != n || slices.Contains(known, false) {
+			//the counter's low bits are at the end, so every
+			//byte must be known
+			return TokenFinding{}, false
+		}
+		for j := range ks {
+			ks[j] ^= first[j]
+		}
+		diffs[i] = new(big.Int).SetBytes(ks)
+		if i > 0 && diffs[i].Sign() == 0 {
+			//c XOR (c+i) is never zero, so repeated tokens can't
+			//come from a counter
+			return TokenFinding{}, false
+		}
+		width = max(width, diffs[i].BitLen())
+	}
+
+	//bit j of c only shows in the carry into bit j+1, so c mod 2^(j+1)
+	//must give every difference mod 2^(j+2)
+	cs := []*big.Int{new(big.Int)}
+	for j := 0; j < width; j++ {
+		next := []*big.Int{}
+		mod := new(big.Int).Lsh(big.NewInt(1), uint(j+2))
+		for _, c := range cs {
+			for _, bit := range []uint{0, 1} {
+				cand := new(big.Int).SetBit(c, j, bit)
+				if counterXorFits(cand, diffs, mod) {
+					next = append(next, cand)
+				}
+			}
+		}
+		if len(next) == 0 {
+			return TokenFinding{}, false
+		}
+		cs = next
+	}
+	c := cs[0]
+	if !counterXorFits(c, diffs, new(big.Int).Lsh(big.NewInt(1), uint(8*n))) {
+		return TokenFinding{}, false
+	}
+
+	f := TokenFinding{Scheme: TokenCounterXor, Counter: c}
+	f.Secret = xorCounter(first, c)
+	f.Note = "Counter bits above those the tokens give away are taken as zero"
+	if len(cs) > 1 {
+		f.Note += fmt.Sprintf("; %d low counter values fit, using the first", len(cs))
+	}
+	lastKs, _ := a.tokenKeystream(a.Tokens[len(a.Tokens)-1])
+	next := make([][]byte, a.Predict)
+	for i := range next {
+		ci := new(big.Int).Add(c, big.NewInt(int64(len(a.Tokens)+i)))
+		next[i] = xorCounter(f.Secret, ci)
+	}
+	f.Next = a.predict(lastKs, next)
+	return f, true
+}
+
+//counterXorFits reports whether c XOR (c+i) matches diffs[i] modulo
+//mod for every i
+func counterXorFits(c *big.Int, diffs []*big.Int, mod *big.Int) bool {
+	for i, d := range diffs {
+		ci := new(big.Int).Add(c, big.NewInt(int64(i)))
+		x := new(big.Int).Xor(c, ci)
+		x.Mod(x, mod)
+		if x.Cmp(new(big.Int).Mod(d, mod)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//xorCounter returns b XORed with c as a big-endian number of len(b)
+//bytes, dropping any higher bits of c
+func xorCounter(b []byte, c *big.Int) []byte {
+	cb := c.Bytes()
+	out := append([]byte{}, b...)
+	for i := 1; i <= len(cb) && i <= len(out); i++ {
+		out[len(out)-i] ^= cb[len(cb)-i]
+	}
+	return out
+}
@@ -0,0 +1,263 @@
+//This file contains recovery of MT19937's state, or seed, from
+//partial outputs by linear algebra over GF(2)
+
+package attacks
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/alanese/cryptopals/mathutil"
+	"github.com/alanese/cryptopals/prng"
+)
+
+//twisterStateBits is the number of bits of MT19937 state which
+//affect its outputs: the top bit of one word and 623 whole words
+const twisterStateBits = 19937
+
+//twisterVars is the number of unknowns in the systems: every bit of
+//the 624 words, though the low 31 bits of the first never appear
+const twisterVars = 624 * 32
+
+//TwisterObservation is some known bits of one output of a Twister:
+//the bits set in Mask have the values they have in Value
+type TwisterObservation struct {
+	Index int
+	Mask  uint32
+	Value uint32
+}
+
+//TwisterTopBits returns the observation that output index has top k
+//bits value, as leaked by Next()>>(32-k) or Bits(k)
+func TwisterTopBits(index int, value uint32, k int) TwisterObservation {
+	mask := ^uint32(0) << (32 - k)
+	return TwisterObservation{index, mask, value << (32 - k) & mask}
+}
+
+//TwisterIntnBits returns the observation made by Intn(n) returning
+//value from output index. Intn takes bits.Len(n) top bits and draws
+//again if they are n or more, which happens with chance 1-n/2^k for
+//every n, half the time for powers of two and 22% for Intn(100). So
+//this only holds if no draw was rejected, and a rejection shifts
+//every later index, making RecoverTwisterState report the
+//observations inconsistent. Leaks from Bits(k) are never redrawn, and
+//those from math/rand's Intn hardly ever (see TwisterRandIntnBits).
+func TwisterIntnBits(index, n, value int) TwisterObservation {
+	return TwisterTopBits(index, uint32(value), bits.Len(uint(n)))
+}
+
+//TwisterRandIntnBits returns the observation made by the call'th
+//Intn(n) of a math/rand.Rand with a Twister as its source returning
+//value, for n below 2^31. Each call takes Int31, the top 31 bits of
+//the second of two outputs, and returns it modulo n, so if 2^t
+//divides n the low t bits of value are bits 1 to t of output
+//2*call+1: two bits for Intn(100), none for odd n. Unlike
+//Twister.Intn, math/rand redraws only if n is not a power of two and
+//Int31 falls in the last partial multiple of n, with chance below
+//n/2^31, so the indices hold in practice.
+func TwisterRandIntnBits(call, n, value int) TwisterObservation {
+	mask := uint32(1)<<bits.TrailingZeros(uint(n)) - 1
+	return TwisterObservation{2*call + 1, mask << 1, uint32(value) & mask << 1}
+}
+
+//TwisterFloat64Bits returns the observations made by Float64
+//returning f from outputs index and index+1: the top 27 bits of the
+//first and the top 26 bits of the second
+func TwisterFloat64Bits(index int, f float64) []TwisterObservation {
+	v := uint64(f * (1 << 53))
+	return []TwisterObservation{
+		TwisterTopBits(index, uint32(v>>26), 27),
+		TwisterTopBits(index+1, uint32(v&(1<<26-1)), 26),
+	}
+}
+
+//twisterWord is a state word as a linear function of the unknowns:
+//bit j is the sum of the unknowns set in the j-th vector
+type twisterWord [32]mathutil.BitVector
+
+//nextTwisterWord returns the word following x0, x1 and xm, as the
+//twist computes it
+func nextTwisterWord(x0, x1, xm *twisterWord) *twisterWord {
+	const a = 0x9908b0df
+	next := new(twisterWord)
+	for j := range next {
+		v := xm[j].Clone()
+		//y is the top bit of x0 and the rest of x1; this is bit j of y>>1
+		switch {
+		case j == 30:
+			v.Xor(x0[31])
+		case j < 30:
+			v.Xor(x1[j+1])
+		}
+		if a>>j&1 == 1 {
+			v.Xor(x1[0])
+		}
+		next[j] = v
+	}
+	return next
+}
+
+//twisterSystem returns the linear system in the bits of a Twister's
+//624-word state array which the observations give, where output k is
+//the tempering of word offset+k of the sequence the array starts.
+//Observations past those which determine the state are left out.
+//Returns a non-nil error if the observations are inconsistent.
+func twisterSystem(obs []TwisterObservation, offset int) (*mathutil.GF2System, error) {
+	obs = append([]TwisterObservation{}, obs...)
+	sort.Slice(obs, func(i, j int) bool { return obs[i].Index < obs[j].Index })
+
+	//temperBits[j] is the state bits whose sum is output bit j
+	var temperBits [32]uint32
+	for i := 0; i < 32; i++ {
+		t := prng.Temper(1 << i)
+		for j := 0; j < 32; j++ {
+			if t>>j&1 == 1 {
+				temperBits[j] |= 1 << i
+			}
+		}
+	}
+
+	ring := make([]*twisterWord, 624)
+	for w := range ring {
+		ring[w] = new(twisterWord)
+		for j := range ring[w] {
+			ring[w][j] = mathutil.NewBitVector(twisterVars)
+			ring[w][j].Set(32*w + j)
+		}
+	}
+	sys := mathutil.NewGF2System(twisterVars)
+	pos := 624
+	for _, o := range obs {
+		if o.Index < 0 {
+			return nil, fmt.Errorf("Negative output index %d", o.Index)
+		}
+		if sys.Rank() == twisterStateBits {
+			//the rest can only be checked, which is much quicker
+			//done by running the recovered twister
+			break
+		}
+		for ; pos <= offset+o.Index; pos++ {
+			ring[pos%624] = nextTwisterWord(ring[pos%624], ring[(pos+1)%624], ring[(pos+397)%624])
+		}
+		word := ring[(offset+o.Index)%624]
+		for j := 0; j < 32; j++ {
+			if o.Mask>>j&1 == 0 {
+				continue
+			}
+			row := mathutil.NewBitVector(twisterVars)
+			for i := 0; i < 32; i++ {
+				if temperBits[j]>>i&1 == 1 {
+					row.Xor(word[i])
+				}
+			}
+			if !sys.Add(row, uint64(o.Value>>j&1)) {
+				return nil, errInconsistent(o.Index)
+			}
+		}
+	}
+	return sys, nil
+}
+
+//RecoverTwisterState recovers an MT19937 state from any known bits of
+//its outputs, which need not be whole or consecutive: truncated
+//values, floats and gappy sequences all work, so long as the index of
+//each output is known. Every known bit is a linear function of the
+//state over GF(2), through the twist and the tempering, so the state
+//is the solution of a linear system. The returned Twister produces
+//output 0 next. Unlike CloneTwisterOutputs, it needs more than 624
+//outputs unless they are whole: about 19937 known bits in all, more
+//if each output leaks only a few. Each index must be exactly the
+//output the bits came from, so leaks through Twister.Intn only work
+//if no draw was rejected (see TwisterIntnBits); math/rand's Intn(100)
+//does work (see TwisterRandIntnBits). Returns a non-nil error
+//saying how many state bits are determined if the observations are
+//not enough, or if they are inconsistent, as misplaced indices make
+//them.
+func RecoverTwisterState(obs []TwisterObservation) (*prng.Twister, error) {
+	//the unknowns are the word before output 0 and the 623 after it,
+	//as the state array just before output 0 is produced
+	sys, err := twisterSystem(obs, 1)
+	if err != nil {
+		return nil, err
+	}
+	if sys.Rank() < twisterStateBits {
+		return nil, fmt.Errorf("Observations determine only %d of %d state bits", sys.Rank(), twisterStateBits)
+	}
+	x := sys.Solve()
+	var state [624]uint32
+	for w := range state {
+		for j := 0; j < 32; j++ {
+			state[w] |= uint32(x.Get(32*w+j)) << j
+		}
+	}
+	var t prng.Twister
+	t.SetState(state, 1)
+	if err := checkTwisterObservations(t, obs); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+//errInconsistent returns the error for observations which no state
+//fits, first found at output index
+func errInconsistent(index int) error {
+	return fmt.Errorf("Observations inconsistent at output %d: wrong values, or indices out of step with the outputs, as after a rejected Intn draw", index)
+}
+
+//checkTwisterObservations checks every observation against the
+//outputs of t, counted from its next one
+func checkTwisterObservations(t prng.Twister, obs []TwisterObservation) error {
+	sorted := append([]TwisterObservation{}, obs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+	next := 0
+	var out uint32
+	for _, o := range sorted {
+		for ; next <= o.Index; next++ {
+			out = t.Next()
+		}
+		if out&o.Mask != o.Value&o.Mask {
+			return errInconsistent(o.Index)
+		}
+	}
+	return nil
+}
+
+//RecoverTwisterSeed recovers the seed given to NewTwister from known
+//bits of its outputs, counted from the first, as RecoverTwisterState
+//does. The unknowns are the array the seed fills in, and the seed
+//follows from its second word, so fewer observations may do than for
+//the state. Returns a non-nil error if the observations are not
+//enough or are inconsistent, or if no seed fits them, as when the
+//twister was seeded some other way.
+func RecoverTwisterSeed(obs []TwisterObservation) (uint32, error) {
+	sys, err := twisterSystem(obs, 624)
+	if err != nil {
+		return 0, err
+	}
+	var x1 uint32
+	for j := 0; j < 32; j++ {
+		v := mathutil.NewBitVector(twisterVars)
+		v.Set(32 + j)
+		b, ok := sys.Eval(v)
+		if !ok {
+			return 0, fmt.Errorf("Observations do not determine the seed (%d of %d state bits determined)", sys.Rank(), twisterStateBits)
+		}
+		x1 |= uint32(b) << j
+	}
+
+	//x1 = 1812433253 * (seed ^ seed>>30) + 1, and undoing the shift
+	//by 30 twice is the identity on 32 bits
+	const mult = 1812433253
+	inv := uint32(mult)
+	for i := 0; i < 5; i++ {
+		inv *= 2 - mult*inv
+	}
+	v := (x1 - 1) * inv
+	seed := v ^ v>>30
+
+	if checkTwisterObservations(prng.NewTwister(seed), obs) != nil {
+		return 0, fmt.Errorf("No seed fits the observations")
+	}
+	return seed, nil
+}
@@ -1,4 +1,4 @@
-package main
+package bytesutil
 
 import (
 	"bytes"
@@ -6,8 +6,10 @@ import (
 	cr "crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"io/ioutil"
 	"math/big"
+	"math/bits"
 	"math/rand"
 )
 
@@ -292,3 +294,46 @@ func SetBit(x uint32, n int) uint32 {
 func MatchBit(tgt, src uint32, n int) uint32 {
 	return tgt ^ ((tgt ^ src) & (1 << n))
 }
+
+//HammingDistance computes the Hamming distance between two bytes
+//(i.e. number of bits that differ).
+func HammingDistance(b1, b2 byte) int {
+	return bits.OnesCount8(uint8(b1 ^ b2))
+}
+
+//HammingDistanceSlice computes the Hamming distance between two
+//byte slices. Returns a non-nil error if the slices are of unequal length
+func HammingDistanceSlice(b1, b2 []byte) (int, error) {
+	if len(b1) != len(b2) {
+		return 0, errors.New("Incompatible byte slices")
+	}
+	ct := 0
+	for i := range b1 {
+		ct += HammingDistance(b1[i], b2[i])
+	}
+	return ct, nil
+}
+
+//NormalizedEditDistance returns the edit distance between two
+//byte slices divided by their length. Returns a non-nil error
+//if the slices are of unequal length
+func NormalizedEditDistance(b1, b2 []byte) (float64, error) {
+	d, err := HammingDistanceSlice(b1, b2)
+	if err != nil {
+		return 0, err
+	}
+	return float64(d) / float64(len(b1)), nil
+}
+
+//XorBufs computes the bitwise xor of two byte slices
+//Returns a non-nil error if the two slices are of different lengths
+func XorBufs(b1, b2 []byte) ([]byte, error) {
+	if len(b1) != len(b2) {
+		return nil, errors.New("Buffers of unequal length")
+	}
+	tmp := make([]byte, len(b1))
+	for i := range b1 {
+		tmp[i] = b1[i] ^ b2[i]
+	}
+	return tmp, nil
+}
@@ -0,0 +1,3 @@
+//Package bytesutil contains helpers for manipulating byte
+//slices and the bits of individual words
+package bytesutil
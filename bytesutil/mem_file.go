@@ -0,0 +1,59 @@
+//This file contains an in-memory file for the io.ReaderAt and
+//io.WriterAt APIs
+
+package bytesutil
+
+import (
+	"errors"
+	"io"
+)
+
+//MemFile is a growable byte slice implementing io.ReaderAt and
+//io.WriterAt, for using random-access APIs without a real file.
+//Writes past the end extend it, zero-filling any gap.
+type MemFile struct {
+	data []byte
+}
+
+//NewMemFile returns a MemFile holding a copy of data
+func NewMemFile(data []byte) *MemFile {
+	return &MemFile{append([]byte{}, data...)}
+}
+
+//Bytes returns the contents. The slice is shared with f until the
+//next write that grows it.
+func (f *MemFile) Bytes() []byte {
+	return f.data
+}
+
+//Size returns the length of the contents
+func (f *MemFile) Size() int64 {
+	return int64(len(f.data))
+}
+
+//ReadAt reads into p from offset off, returning io.EOF if it
+//reaches the end before filling p
+func (f *MemFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("Negative offset")
+	}
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+//WriteAt writes p at offset off, growing the contents if needed
+func (f *MemFile) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("Negative offset")
+	}
+	if end := off + int64(len(p)); end > int64(len(f.data)) {
+		f.data = append(f.data, make([]byte, end-int64(len(f.data)))...)
+	}
+	return copy(f.data[off:], p), nil
+}
@@ -0,0 +1,93 @@
+//This file contains random-access CTR mode, for encrypting and
+//decrypting at any offset without touching the rest of the data
+
+package ciphers
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+)
+
+//SeekableCTR is CTR mode addressed by byte offset rather than as a
+//stream. Only the keystream blocks covering the bytes asked for are
+//computed, so editing a few bytes of a large ciphertext is cheap.
+type SeekableCTR struct {
+	b       cipher.Block
+	nonce   []byte
+	counter CounterFunc
+}
+
+//NewSeekableCTR returns random-access CTR mode over b, with
+//keystream blocks made by CTRKeystreamBlock
+func NewSeekableCTR(b cipher.Block, nonce []byte, counter CounterFunc) *SeekableCTR {
+	if len(nonce)+len(counter(0)) != b.BlockSize() {
+		panic("Nonce and counter must make up one block")
+	}
+	return &SeekableCTR{b, append([]byte{}, nonce...), counter}
+}
+
+//XORKeyStreamAt XORs src with the keystream starting at byte offset
+//off, writing the result to dst. dst and src may overlap exactly.
+func (s *SeekableCTR) XORKeyStreamAt(dst, src []byte, off int64) {
+	if off < 0 {
+		panic("Negative offset")
+	}
+	if len(dst) < len(src) {
+		panic("Output smaller than input")
+	}
+	bs := int64(s.b.BlockSize())
+	for done := 0; done < len(src); {
+		pos := off + int64(done)
+		block := CTRKeystreamBlock(s.b, s.nonce, s.counter, int(pos/bs))
+		n := min(len(src)-done, int(bs-pos%bs))
+		xorInto(dst[done:done+n], src[done:], block[pos%bs:])
+		done += n
+	}
+}
+
+//KeystreamAt returns n bytes of keystream starting at byte offset off
+func (s *SeekableCTR) KeystreamAt(off int64, n int) []byte {
+	ks := make([]byte, n)
+	s.XORKeyStreamAt(ks, ks, off)
+	return ks
+}
+
+//CTRFile is CTR-encrypted data in storage which can be read and
+//written at any offset, such as an *os.File. Reads decrypt and writes
+//encrypt, each touching only the blocks it covers. Either side may be
+//missing for read-only or write-only use.
+type CTRFile struct {
+	ctr *SeekableCTR
+	r   io.ReaderAt
+	w   io.WriterAt
+}
+
+//NewCTRFile returns a CTRFile reading ciphertext from r and writing
+//it to w, usually the same storage. Either may be nil.
+func NewCTRFile(ctr *SeekableCTR, r io.ReaderAt, w io.WriterAt) *CTRFile {
+	return &CTRFile{ctr, r, w}
+}
+
+//ReadAt reads len(p) bytes of ciphertext at offset off and decrypts
+//them into p. Like any io.ReaderAt, it returns a non-nil error when
+//fewer than len(p) bytes are read, and those that are are decrypted.
+func (f *CTRFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.r == nil {
+		return 0, errors.New("CTRFile is write-only")
+	}
+	n, err := f.r.ReadAt(p, off)
+	f.ctr.XORKeyStreamAt(p[:n], p[:n], off)
+	return n, err
+}
+
+//WriteAt encrypts p as the plaintext at offset off and writes the
+//ciphertext there, leaving the rest of the storage alone
+func (f *CTRFile) WriteAt(p []byte, off int64) (int, error) {
+	if f.w == nil {
+		return 0, errors.New("CTRFile is read-only")
+	}
+	ctext := make([]byte, len(p))
+	f.ctr.XORKeyStreamAt(ctext, p, off)
+	return f.w.WriteAt(ctext, off)
+}
@@ -0,0 +1,3 @@
+//Package ciphers contains block and stream cipher
+//primitives along with the padding schemes they use
+package ciphers
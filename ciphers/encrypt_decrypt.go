@@ -0,0 +1,122 @@
+//This file contains assorted encryption and decryption functions
+
+package ciphers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rc4"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/prng"
+)
+
+//newAES returns an AES cipher.Block for the given key,
+//panicking if the key is not a valid AES key length
+func newAES(key []byte) cipher.Block {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+//GenerateCTRKeystreamBlock generates a CTR keystream block
+//with the given key, nonce, and counter. len(nonce) + len(counter)
+//must be the block length.
+func GenerateCTRKeystreamBlock(key, nonce, counter []byte) []byte {
+	return CTRKeystreamBlock(newAES(key), nonce, func(int) []byte { return counter }, 0)
+}
+
+//GenerateCTRKeystream generates the given number of blocks
+//of an AES-CTR keystream given key, nonce, and a counter function.
+func GenerateCTRKeystream(blocks int, key, nonce []byte, counter func(int) []byte) []byte {
+	return CryptStream(NewCTR(newAES(key), nonce, counter), make([]byte, 16*blocks))
+}
+
+//LittleEndianCounter is a counter function for use with
+//GenerateCTRKeystream.
+func LittleEndianCounter(i int) []byte {
+	counter := make([]byte, 8)
+	for j := 0; j < 8; j++ {
+		counter[j] = byte((i >> (j * 8)) & 0xFF)
+	}
+	return counter
+}
+
+//DecryptAESCBC decrypts a byte slice using AES-CBC
+func DecryptAESCBC(ctext []byte, key []byte, iv []byte) []byte {
+	return CryptBlocks(NewCBCDecrypter(newAES(key), iv), ctext)
+}
+
+//DecryptAESECB decrypts a ciphertext encrypted with AES-ECB
+func DecryptAESECB(ctext []byte, key []byte) []byte {
+	return CryptBlocks(NewECBDecrypter(newAES(key)), ctext)
+}
+
+//EncryptAESCBC encrypts a byte slice using AES-CBC
+func EncryptAESCBC(ptext, key, iv []byte) []byte {
+	return CryptBlocks(NewCBCEncrypter(newAES(key), iv), ptext)
+}
+
+//EncryptAESECB encrypts a ciphertext with AES-ECB
+func EncryptAESECB(ptext []byte, key []byte) []byte {
+	return CryptBlocks(NewECBEncrypter(newAES(key)), ptext)
+}
+
+//EncryptAESCTRBlock encrypts a single 16-byte block of text using AES-CTR
+//blockNum is the zero-indexed number of the block in the stream (i.e. 3 to
+//use the fourth block in the keystream)
+func EncryptAESCTRBlock(ptext, key, nonce []byte, blockNum int) []byte {
+	keyBlock := CTRKeystreamBlock(newAES(key), nonce, LittleEndianCounter, blockNum)
+	c, _ := bytesutil.XorBufs(keyBlock, ptext)
+	return c
+}
+
+//EncryptAESCTR encrypts a given plaintext using AES-CTR with the given key
+//and fixed nonce. Uses LittleEndianCounter as the counter function.
+func EncryptAESCTR(ptext, key, nonce []byte) []byte {
+	return CryptStream(NewCTR(newAES(key), nonce, LittleEndianCounter), ptext)
+}
+
+//EncryptMT19937Stream encrypts the given bytes using
+//a mersenne twister seeded with the key as a keystream
+//The bytes of each uint32 value drawn from the twister
+//are used beginning with the least significant.
+func EncryptMT19937Stream(ptext []byte, key uint32) []byte {
+	t := prng.NewTwister(key)
+	ctext := make([]byte, len(ptext))
+	bitmask := uint32(0x000000FF)
+	nextKeyChunk := uint32(0)
+	for i, b := range ptext {
+		byteInChunk := i % 4
+		if byteInChunk == 0 {
+			nextKeyChunk = t.Next()
+		}
+
+		keyByte := byte((nextKeyChunk >> (8 * byteInChunk)) & bitmask)
+		ctext[i] = b ^ keyByte
+
+	}
+
+	return ctext
+}
+
+//EncryptRC4 encrypts the given plaintext under RC4
+//with the given key
+func EncryptRC4(ptext, key []byte) []byte {
+	c, _ := rc4.NewCipher(key)
+	ctext := make([]byte, len(ptext))
+	c.XORKeyStream(ctext, ptext)
+	return ctext
+}
+
+//XorEncrypt encrypts a plaintext byte slice with repeating-key XOR
+func XorEncrypt(plaintext, key []byte) []byte {
+	ct := make([]byte, len(plaintext))
+	keyLength := len(key)
+	for i, v := range plaintext {
+		ct[i] = v ^ (key[i%keyLength])
+	}
+	return ct
+}
@@ -0,0 +1,141 @@
+//This file contains GHASH and GCM authenticated encryption
+
+package ciphers
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/mathutil"
+)
+
+//GCMNonceSize is the nonce size GCM supports
+const GCMNonceSize = 12
+
+//GHASHBlocks returns the blocks GHASH hashes for the given additional
+//data and ciphertext: each zero-padded to a whole number of blocks,
+//then a block holding both their lengths in bits
+func GHASHBlocks(aad, ctext []byte) []mathutil.GF128 {
+	blocks := []mathutil.GF128{}
+	for _, data := range [][]byte{aad, ctext} {
+		for i := 0; i < len(data); i += 16 {
+			block := make([]byte, 16)
+			copy(block, data[i:])
+			blocks = append(blocks, mathutil.GF128FromBytes(block))
+		}
+	}
+	lengths := make([]byte, 16)
+	binary.BigEndian.PutUint64(lengths, uint64(len(aad))*8)
+	binary.BigEndian.PutUint64(lengths[8:], uint64(len(ctext))*8)
+	return append(blocks, mathutil.GF128FromBytes(lengths))
+}
+
+//GHASH returns GHASH under authentication key h of the given
+//additional data and ciphertext: the polynomial whose coefficients
+//are GHASHBlocks, evaluated at h. The blocks are read in place
+//rather than built with GHASHBlocks, since messages can be long.
+func GHASH(h mathutil.GF128, aad, ctext []byte) []byte {
+	t := mathutil.NewGF128Table(h)
+	var y mathutil.GF128
+	block := make([]byte, 16)
+	for _, data := range [][]byte{aad, ctext} {
+		for i := 0; i < len(data); i += 16 {
+			if len(data)-i < 16 {
+				clear(block)
+			}
+			copy(block, data[i:])
+			y = t.Mul(y.Add(mathutil.GF128FromBytes(block)))
+		}
+	}
+	binary.BigEndian.PutUint64(block, uint64(len(aad))*8)
+	binary.BigEndian.PutUint64(block[8:], uint64(len(ctext))*8)
+	return t.Mul(y.Add(mathutil.GF128FromBytes(block))).Bytes()
+}
+
+//gcmCounter is the CounterFunc for GCM: CTR block i uses the 32-bit
+//big-endian counter i+2, since counter 1 masks the tag
+func gcmCounter(i int) []byte {
+	counter := make([]byte, 4)
+	binary.BigEndian.PutUint32(counter, uint32(i+2))
+	return counter
+}
+
+//GCM is AES-GCM, or GCM over any other 16-byte block cipher, with
+//tags truncated to a chosen size. It implements cipher.AEAD.
+type GCM struct {
+	b       cipher.Block
+	h       mathutil.GF128
+	tagSize int
+}
+
+//NewGCM returns GCM over b with tags of tagSize bytes, from 1
+//to 16. Tags shorter than 12 bytes are very weak; they are allowed
+//for the attacks. Returns a non-nil error if b does not have 16-byte
+//blocks or tagSize is out of range.
+func NewGCM(b cipher.Block, tagSize int) (*GCM, error) {
+	if b.BlockSize() != 16 {
+		return nil, errors.New("GCM needs a 16-byte block cipher")
+	}
+	if tagSize < 1 || tagSize > 16 {
+		return nil, errors.New("Tag size must be from 1 to 16 bytes")
+	}
+	h := make([]byte, 16)
+	b.Encrypt(h, h)
+	return &GCM{b, mathutil.GF128FromBytes(h), tagSize}, nil
+}
+
+//AuthKey returns the authentication key H, the encryption of the
+//zero block. It is secret; it is here for checking the attacks.
+func (g *GCM) AuthKey() mathutil.GF128 {
+	return g.h
+}
+
+//NonceSize returns 12
+func (g *GCM) NonceSize() int {
+	return GCMNonceSize
+}
+
+//Overhead returns the tag size
+func (g *GCM) Overhead() int {
+	return g.tagSize
+}
+
+//Tag returns the tag, truncated to the tag size, for a ciphertext
+//and additional data sealed under the given nonce
+func (g *GCM) Tag(nonce, aad, ctext []byte) []byte {
+	mask := CTRKeystreamBlock(g.b, nonce, gcmCounter, -1)
+	tag, _ := bytesutil.XorBufs(GHASH(g.h, aad, ctext), mask)
+	return tag[:g.tagSize]
+}
+
+//Seal encrypts and authenticates plaintext and authenticates
+//additionalData, appending the ciphertext and tag to dst
+func (g *GCM) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != GCMNonceSize {
+		panic("Incorrect nonce length given to GCM")
+	}
+	ctext := CryptStream(NewCTR(g.b, nonce, gcmCounter), plaintext)
+	dst = append(dst, ctext...)
+	return append(dst, g.Tag(nonce, additionalData, ctext)...)
+}
+
+//Open checks the tag on ciphertext and additionalData and, if it is
+//right, decrypts ciphertext and appends the plaintext to dst. Returns
+//a non-nil error if the tag is wrong.
+func (g *GCM) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != GCMNonceSize {
+		panic("Incorrect nonce length given to GCM")
+	}
+	if len(ciphertext) < g.tagSize {
+		return nil, errors.New("Ciphertext too short")
+	}
+	ctext := ciphertext[:len(ciphertext)-g.tagSize]
+	tag := ciphertext[len(ctext):]
+	if subtle.ConstantTimeCompare(tag, g.Tag(nonce, additionalData, ctext)) != 1 {
+		return nil, errors.New("Message authentication failed")
+	}
+	return append(dst, CryptStream(NewCTR(g.b, nonce, gcmCounter), ctext)...), nil
+}
@@ -0,0 +1,281 @@
+//This file contains block cipher modes of operation, written
+//once over cipher.Block so they work with AES, Twofish, DES
+//or a toy cipher alike
+
+package ciphers
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+)
+
+//CounterFunc returns the counter half of CTR keystream block i,
+//which follows the nonce to make up a full block
+type CounterFunc func(i int) []byte
+
+//BigEndianCounter is a CounterFunc giving an 8-byte
+//big-endian block number
+func BigEndianCounter(i int) []byte {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(i))
+	return counter
+}
+
+//checkBlocks panics if src is not a whole number of blocks
+//or dst is shorter than src
+func checkBlocks(blockSize int, dst, src []byte) {
+	if len(src)%blockSize != 0 {
+		panic("Input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("Output smaller than input")
+	}
+}
+
+//xorInto sets dst[i] = a[i] ^ b[i] for i < len(dst)
+func xorInto(dst, a, b []byte) {
+	for i := range dst {
+		dst[i] = a[i] ^ b[i]
+	}
+}
+
+//ecb is the ECB BlockMode
+type ecb struct {
+	b       cipher.Block
+	decrypt bool
+}
+
+//NewECBEncrypter returns a BlockMode encrypting in ECB mode
+func NewECBEncrypter(b cipher.Block) cipher.BlockMode {
+	return ecb{b, false}
+}
+
+//NewECBDecrypter returns a BlockMode decrypting in ECB mode
+func NewECBDecrypter(b cipher.Block) cipher.BlockMode {
+	return ecb{b, true}
+}
+
+//BlockSize returns the block size of the underlying cipher
+func (m ecb) BlockSize() int {
+	return m.b.BlockSize()
+}
+
+//CryptBlocks encrypts or decrypts each block of src on its own
+func (m ecb) CryptBlocks(dst, src []byte) {
+	bs := m.b.BlockSize()
+	checkBlocks(bs, dst, src)
+	for i := 0; i < len(src); i += bs {
+		if m.decrypt {
+			m.b.Decrypt(dst[i:i+bs], src[i:i+bs])
+		} else {
+			m.b.Encrypt(dst[i:i+bs], src[i:i+bs])
+		}
+	}
+}
+
+//chained is the CBC and PCBC BlockMode. Each block is XORed with
+//the chaining value before encryption; in CBC the chaining value is
+//the previous ciphertext block, and in PCBC the previous ciphertext
+//block XORed with the previous plaintext block.
+type chained struct {
+	b          cipher.Block
+	chain      []byte
+	decrypt    bool
+	propagate  bool
+	blockSize  int
+	tmp, saved []byte
+}
+
+//newChained returns a chained BlockMode, panicking if the IV
+//is not one block long
+func newChained(b cipher.Block, iv []byte, decrypt, propagate bool) cipher.BlockMode {
+	bs := b.BlockSize()
+	if len(iv) != bs {
+		panic("IV length must equal block size")
+	}
+	return &chained{
+		b:         b,
+		chain:     append([]byte{}, iv...),
+		decrypt:   decrypt,
+		propagate: propagate,
+		blockSize: bs,
+		tmp:       make([]byte, bs),
+		saved:     make([]byte, bs),
+	}
+}
+
+//NewCBCEncrypter returns a BlockMode encrypting in CBC mode
+func NewCBCEncrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return newChained(b, iv, false, false)
+}
+
+//NewCBCDecrypter returns a BlockMode decrypting in CBC mode
+func NewCBCDecrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return newChained(b, iv, true, false)
+}
+
+//NewPCBCEncrypter returns a BlockMode encrypting in PCBC mode,
+//where a change to any block scrambles every block after it
+func NewPCBCEncrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return newChained(b, iv, false, true)
+}
+
+//NewPCBCDecrypter returns a BlockMode decrypting in PCBC mode
+func NewPCBCDecrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return newChained(b, iv, true, true)
+}
+
+//BlockSize returns the block size of the underlying cipher
+func (m *chained) BlockSize() int {
+	return m.blockSize
+}
+
+//CryptBlocks encrypts or decrypts src, carrying the chaining
+//value over to the next call
+func (m *chained) CryptBlocks(dst, src []byte) {
+	bs := m.blockSize
+	checkBlocks(bs, dst, src)
+	for i := 0; i < len(src); i += bs {
+		in, out := src[i:i+bs], dst[i:i+bs]
+		//save the input, in case dst and src overlap
+		copy(m.saved, in)
+		if m.decrypt {
+			m.b.Decrypt(m.tmp, m.saved)
+			xorInto(out, m.tmp, m.chain)
+			if m.propagate {
+				xorInto(m.chain, m.saved, out)
+			} else {
+				copy(m.chain, m.saved)
+			}
+		} else {
+			xorInto(m.tmp, m.saved, m.chain)
+			m.b.Encrypt(out, m.tmp)
+			if m.propagate {
+				xorInto(m.chain, m.saved, out)
+			} else {
+				copy(m.chain, out)
+			}
+		}
+	}
+}
+
+//keystream is a cipher.Stream which XORs its input with blocks
+//produced by next, which is given the previous input block
+//(ciphertext, in CFB) and returns the next keystream block
+type keystream struct {
+	next  func(prev []byte) []byte
+	block []byte //current keystream block
+	prev  []byte //ciphertext of the current block so far, for CFB
+	used  int
+	cfb   bool
+	dec   bool
+}
+
+//XORKeyStream XORs src with the keystream into dst
+func (s *keystream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("Output smaller than input")
+	}
+	for i, b := range src {
+		if s.used == len(s.block) {
+			s.block = s.next(s.prev)
+			s.prev = s.prev[:0]
+			s.used = 0
+		}
+		out := b ^ s.block[s.used]
+		if s.cfb {
+			if s.dec {
+				s.prev = append(s.prev, b)
+			} else {
+				s.prev = append(s.prev, out)
+			}
+		}
+		dst[i] = out
+		s.used++
+	}
+}
+
+//NewCFBEncrypter returns a Stream encrypting in full-block CFB mode
+func NewCFBEncrypter(b cipher.Block, iv []byte) cipher.Stream {
+	return newCFB(b, iv, false)
+}
+
+//NewCFBDecrypter returns a Stream decrypting in full-block CFB mode
+func NewCFBDecrypter(b cipher.Block, iv []byte) cipher.Stream {
+	return newCFB(b, iv, true)
+}
+
+//newCFB returns a CFB Stream, panicking if the IV is not one block long
+func newCFB(b cipher.Block, iv []byte, decrypt bool) cipher.Stream {
+	bs := b.BlockSize()
+	if len(iv) != bs {
+		panic("IV length must equal block size")
+	}
+	return &keystream{
+		next: func(prev []byte) []byte {
+			out := make([]byte, bs)
+			b.Encrypt(out, prev)
+			return out
+		},
+		prev: append(make([]byte, 0, bs), iv...),
+		cfb:  true,
+		dec:  decrypt,
+	}
+}
+
+//NewOFB returns a Stream encrypting or decrypting in OFB mode
+func NewOFB(b cipher.Block, iv []byte) cipher.Stream {
+	bs := b.BlockSize()
+	if len(iv) != bs {
+		panic("IV length must equal block size")
+	}
+	state := append([]byte{}, iv...)
+	return &keystream{
+		next: func([]byte) []byte {
+			b.Encrypt(state, state)
+			return append([]byte{}, state...)
+		},
+	}
+}
+
+//CTRKeystreamBlock returns block i of a CTR keystream: the
+//encryption of nonce followed by counter(i). len(nonce) plus the
+//length of the counter must be the block size.
+func CTRKeystreamBlock(b cipher.Block, nonce []byte, counter CounterFunc, i int) []byte {
+	in := append(append([]byte{}, nonce...), counter(i)...)
+	if len(in) != b.BlockSize() {
+		panic("Nonce and counter must make up one block")
+	}
+	out := make([]byte, len(in))
+	b.Encrypt(out, in)
+	return out
+}
+
+//NewCTR returns a Stream encrypting or decrypting in CTR mode,
+//with keystream blocks made by CTRKeystreamBlock
+func NewCTR(b cipher.Block, nonce []byte, counter CounterFunc) cipher.Stream {
+	i := 0
+	return &keystream{
+		next: func([]byte) []byte {
+			block := CTRKeystreamBlock(b, nonce, counter, i)
+			i++
+			return block
+		},
+	}
+}
+
+//CryptStream returns src XORed with the keystream of s; it
+//encrypts or decrypts all of src with a Stream in one go
+func CryptStream(s cipher.Stream, src []byte) []byte {
+	dst := make([]byte, len(src))
+	s.XORKeyStream(dst, src)
+	return dst
+}
+
+//CryptBlocks returns the result of running m over src, which
+//must be a whole number of blocks
+func CryptBlocks(m cipher.BlockMode, src []byte) []byte {
+	dst := make([]byte, len(src))
+	m.CryptBlocks(dst, src)
+	return dst
+}
@@ -0,0 +1,107 @@
+package ciphers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/alanese/cryptopals/bytesutil"
+)
+
+//PKCSPad pads a byte slice using PKCS#7 for the given block size
+func PKCSPad(txt []byte, blockSize int) []byte {
+	toAdd := blockSize - (len(txt) % blockSize)
+	for i := 0; i < toAdd; i++ {
+		txt = append(txt, byte(toAdd))
+	}
+	return txt
+}
+
+//PKCS15Pad pads the input to the given length per PKCS#1v1.5
+//(block type 02). Returns a non-nil error if the given length
+//is too short to properly pad the message.
+func PKCS15Pad(txt []byte, length int) ([]byte, error) {
+	if len(txt) > length-11 {
+		return nil, fmt.Errorf("Length too short to accommodate padding")
+	}
+	padded := []byte{0x00, 0x02}
+	for len(padded) < length-len(txt)-1 {
+		padded = append(padded, byte(rand.Intn(254)+1))
+	}
+	padded = append(padded, 0x00)
+	padded = append(padded, txt...)
+	return padded, nil
+}
+
+//RSAPKCS1Validate determines whether the byte slice is
+//properly padded for RSA encryption according to PKCS#1v1.5
+//Not a cryptographically secure check as it has a timing leak
+func RSAPKCS1Validate(eb []byte) bool {
+	if len(eb) < 11 {
+		return false
+	}
+	if eb[0] != 0x00 {
+		return false
+	}
+	if eb[1] != 0x02 {
+		return false
+	}
+	for i := 2; i < 9; i++ {
+		if eb[i] == 0x00 {
+			return false
+		}
+	}
+	for i := 9; i < len(eb); i++ {
+		if eb[i] == 0x00 {
+			return true
+		}
+	}
+	return false
+}
+
+//StripPKCS15Padding strips PKCS#1v1.5 padding (block type 02)
+//from the byte slice. Returns a non-nil error if the block
+//is not validly padded.
+func StripPKCS15Padding(eb []byte) ([]byte, error) {
+	if len(eb) < 11 {
+		return nil, fmt.Errorf("Block is not PKCS1.5-padded")
+	}
+	if eb[0] != 0x00 || eb[1] != 0x02 {
+		return nil, fmt.Errorf("Block is not PKCS1.5-padded")
+	}
+	for i := 2; i < 9; i++ {
+		if eb[i] == 0x00 {
+			return nil, fmt.Errorf("Block is not PKCS1.5-padded")
+		}
+	}
+	for i := 9; i < len(eb)-1; i++ {
+		if eb[i] == 0x00 {
+			return eb[i+1:], nil
+		}
+	}
+	if eb[len(eb)-1] == 0x00 {
+		return []byte{}, nil
+	}
+	return nil, fmt.Errorf("Block is not PKCS1.5-padded")
+}
+
+//StripPKCS7Padding strips the PKCS#7 padding from
+//a byte slice. Returns a non-nil error if the text
+//length is not a multiple of the block size, or
+//if the text is not correctly PKCS padded.
+func StripPKCS7Padding(txt []byte, blockLength int) ([]byte, error) {
+	if len(txt)%blockLength != 0 {
+		return nil, errors.New("Text length not a multiple of block size")
+	}
+
+	for i := 1; i <= blockLength; i++ {
+		testEnd := bytesutil.NCopiesOfN(i)
+		if bytes.HasSuffix(txt, testEnd) {
+			return txt[:len(txt)-i], nil
+		}
+	}
+
+	return nil, errors.New("Text is not PKCS7-padded")
+
+}
@@ -0,0 +1,251 @@
+//This file contains io.Reader and io.Writer wrappers for the
+//modes in modes.go and xts.go. The stream modes (CFB, OFB and CTR)
+//return a cipher.Stream, which cipher.StreamReader and
+//cipher.StreamWriter already wrap.
+
+package ciphers
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+)
+
+//blockModeWriter is the io.WriteCloser returned by NewBlockModeWriter
+type blockModeWriter struct {
+	w   io.Writer
+	m   cipher.BlockMode
+	pad bool
+	buf []byte
+	err error //the first error from w, returned by every later call
+}
+
+//NewBlockModeWriter returns a writer which encrypts with m, a block
+//mode such as ECB, CBC or PCBC, and writes the ciphertext to w. Whole
+//blocks are written as soon as they are complete. Close writes the
+//last block, padded with PKCS#7 if pad is true; otherwise Close returns
+//an error if the input was not a whole number of blocks. Close does not
+//close w. Once a write to w fails the mode's chaining state is lost,
+//so every later Write and Close returns the same error.
+func NewBlockModeWriter(w io.Writer, m cipher.BlockMode, pad bool) io.WriteCloser {
+	return &blockModeWriter{w: w, m: m, pad: pad}
+}
+
+//Write encrypts and writes every whole block of buffered input
+func (bw *blockModeWriter) Write(p []byte) (int, error) {
+	if bw.err != nil {
+		return 0, bw.err
+	}
+	bw.buf = append(bw.buf, p...)
+	bs := bw.m.BlockSize()
+	n := len(bw.buf) / bs * bs
+	if n == 0 {
+		return len(p), nil
+	}
+	out := CryptBlocks(bw.m, bw.buf[:n])
+	bw.buf = append(bw.buf[:0], bw.buf[n:]...)
+	if _, err := bw.w.Write(out); err != nil {
+		bw.err = err
+		return 0, err
+	}
+	return len(p), nil
+}
+
+//Close writes the last, padded block
+func (bw *blockModeWriter) Close() error {
+	if bw.err != nil {
+		return bw.err
+	}
+	if bw.pad {
+		bw.buf = PKCSPad(bw.buf, bw.m.BlockSize())
+	} else if len(bw.buf) > 0 {
+		return errors.New("Input not full blocks")
+	}
+	if len(bw.buf) == 0 {
+		return nil
+	}
+	_, bw.err = bw.w.Write(CryptBlocks(bw.m, bw.buf))
+	bw.buf = nil
+	return bw.err
+}
+
+//blockModeReader is the io.Reader returned by NewBlockModeReader
+type blockModeReader struct {
+	r     io.Reader
+	m     cipher.BlockMode
+	unpad bool
+	in    []byte //ciphertext read but not yet decrypted
+	out   []byte //plaintext ready to be returned
+	held  []byte //last plaintext block, held back until EOF to unpad
+	eof   bool
+	err   error
+}
+
+//NewBlockModeReader returns a reader which decrypts the ciphertext
+//read from r with m, a block mode such as ECB, CBC or PCBC. If unpad
+//is true the PKCS#7 padding is removed, and bad padding is returned as
+//an error at the end of the stream.
+func NewBlockModeReader(r io.Reader, m cipher.BlockMode, unpad bool) io.Reader {
+	return &blockModeReader{r: r, m: m, unpad: unpad}
+}
+
+//fill reads and decrypts more ciphertext
+func (br *blockModeReader) fill() {
+	bs := br.m.BlockSize()
+	tmp := make([]byte, 16*bs)
+	n, err := br.r.Read(tmp)
+	br.in = append(br.in, tmp[:n]...)
+	if err == io.EOF {
+		br.eof = true
+	} else if err != nil {
+		br.err = err
+		return
+	}
+	whole := len(br.in) / bs * bs
+	if whole > 0 {
+		plain := CryptBlocks(br.m, br.in[:whole])
+		br.in = append(br.in[:0], br.in[whole:]...)
+		if br.unpad {
+			plain = append(br.held, plain...)
+			br.held = append([]byte{}, plain[len(plain)-bs:]...)
+			plain = plain[:len(plain)-bs]
+		}
+		br.out = append(br.out, plain...)
+	}
+	if !br.eof {
+		return
+	}
+	if len(br.in) > 0 {
+		br.err = errors.New("Input not full blocks")
+		return
+	}
+	if br.unpad {
+		if br.held == nil {
+			br.err = errors.New("Missing padding")
+			return
+		}
+		last, err := StripPKCS7Padding(br.held, bs)
+		br.held = nil
+		if err != nil {
+			br.err = err
+			return
+		}
+		br.out = append(br.out, last...)
+	}
+	br.err = io.EOF
+}
+
+//Read returns decrypted plaintext
+func (br *blockModeReader) Read(p []byte) (int, error) {
+	for len(br.out) == 0 && br.err == nil {
+		br.fill()
+	}
+	n := copy(p, br.out)
+	br.out = br.out[n:]
+	if len(br.out) == 0 && br.err != nil {
+		return n, br.err
+	}
+	return n, nil
+}
+
+//xtsWriter is the io.WriteCloser returned by NewXTSWriter
+type xtsWriter struct {
+	w          io.Writer
+	x          *XTS
+	sectorSize int
+	sector     uint64
+	buf        []byte
+	err        error //the first error from w, returned by every later call
+}
+
+//NewXTSWriter returns a writer which encrypts with x in sectors of
+//sectorSize bytes, numbered from firstSector, and writes the ciphertext
+//to w. Close encrypts the last sector, which may be short but must be
+//at least 16 bytes. Close does not close w. Once a write to w fails
+//every later Write and Close returns the same error.
+func NewXTSWriter(w io.Writer, x *XTS, sectorSize int, firstSector uint64) io.WriteCloser {
+	return &xtsWriter{w: w, x: x, sectorSize: sectorSize, sector: firstSector}
+}
+
+//Write encrypts and writes every whole sector of buffered input
+func (xw *xtsWriter) Write(p []byte) (int, error) {
+	if xw.err != nil {
+		return 0, xw.err
+	}
+	xw.buf = append(xw.buf, p...)
+	for len(xw.buf) >= xw.sectorSize {
+		out := make([]byte, xw.sectorSize)
+		xw.x.EncryptSector(out, xw.buf[:xw.sectorSize], xw.sector)
+		xw.sector++
+		xw.buf = xw.buf[xw.sectorSize:]
+		if _, err := xw.w.Write(out); err != nil {
+			xw.err = err
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+//Close encrypts and writes the last sector
+func (xw *xtsWriter) Close() error {
+	if xw.err != nil {
+		return xw.err
+	}
+	if len(xw.buf) == 0 {
+		return nil
+	}
+	if len(xw.buf) < xtsBlockSize {
+		return errors.New("Last sector shorter than a block")
+	}
+	out := make([]byte, len(xw.buf))
+	xw.x.EncryptSector(out, xw.buf, xw.sector)
+	xw.buf = nil
+	_, xw.err = xw.w.Write(out)
+	return xw.err
+}
+
+//xtsReader is the io.Reader returned by NewXTSReader
+type xtsReader struct {
+	r          io.Reader
+	x          *XTS
+	sectorSize int
+	sector     uint64
+	out        []byte
+	err        error
+}
+
+//NewXTSReader returns a reader which decrypts the ciphertext read from
+//r with x in sectors of sectorSize bytes, numbered from firstSector.
+//The last sector may be short.
+func NewXTSReader(r io.Reader, x *XTS, sectorSize int, firstSector uint64) io.Reader {
+	return &xtsReader{r: r, x: x, sectorSize: sectorSize, sector: firstSector}
+}
+
+//Read returns decrypted plaintext
+func (xr *xtsReader) Read(p []byte) (int, error) {
+	for len(xr.out) == 0 && xr.err == nil {
+		in := make([]byte, xr.sectorSize)
+		n, err := io.ReadFull(xr.r, in)
+		switch {
+		case err == io.EOF:
+			xr.err = io.EOF
+		case err == io.ErrUnexpectedEOF && n < xtsBlockSize:
+			xr.err = errors.New("Last sector shorter than a block")
+		case err == io.ErrUnexpectedEOF || err == nil:
+			xr.out = make([]byte, n)
+			xr.x.DecryptSector(xr.out, in[:n], xr.sector)
+			xr.sector++
+			if err != nil {
+				xr.err = io.EOF
+			}
+		default:
+			xr.err = err
+		}
+	}
+	n := copy(p, xr.out)
+	xr.out = xr.out[n:]
+	if len(xr.out) == 0 && xr.err != nil {
+		return n, xr.err
+	}
+	return n, nil
+}
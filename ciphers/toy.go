@@ -0,0 +1,60 @@
+//This file contains a toy block cipher for exercising the
+//modes and attacks with something other than AES
+
+package ciphers
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+//toyFeistel is the cipher returned by NewToyFeistel
+type toyFeistel struct {
+	key    []byte
+	rounds int
+}
+
+//NewToyFeistel returns a toy 8-byte block cipher: a Feistel network
+//with the given number of rounds, whose round function is SHA-256 of
+//the key, round number and half-block. It is slow and has had no
+//analysis; it is only here to show that the modes and attacks do not
+//depend on AES.
+func NewToyFeistel(key []byte, rounds int) cipher.Block {
+	return toyFeistel{append([]byte{}, key...), rounds}
+}
+
+//BlockSize returns 8
+func (t toyFeistel) BlockSize() int {
+	return 8
+}
+
+//f is the round function
+func (t toyFeistel) f(round int, half uint32) uint32 {
+	in := make([]byte, 0, len(t.key)+8)
+	in = append(in, t.key...)
+	in = binary.BigEndian.AppendUint32(in, uint32(round))
+	in = binary.BigEndian.AppendUint32(in, half)
+	sum := sha256.Sum256(in)
+	return binary.BigEndian.Uint32(sum[:])
+}
+
+//Encrypt encrypts the first block of src into dst
+func (t toyFeistel) Encrypt(dst, src []byte) {
+	l, r := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := 0; i < t.rounds; i++ {
+		l, r = r, l^t.f(i, r)
+	}
+	binary.BigEndian.PutUint32(dst, l)
+	binary.BigEndian.PutUint32(dst[4:], r)
+}
+
+//Decrypt decrypts the first block of src into dst
+func (t toyFeistel) Decrypt(dst, src []byte) {
+	l, r := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := t.rounds - 1; i >= 0; i-- {
+		l, r = r^t.f(i, l), l
+	}
+	binary.BigEndian.PutUint32(dst, l)
+	binary.BigEndian.PutUint32(dst[4:], r)
+}
@@ -0,0 +1,115 @@
+//This file contains XTS mode, as used for disk encryption
+
+package ciphers
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+)
+
+//xtsBlockSize is the only block size XTS is defined for
+const xtsBlockSize = 16
+
+//XTS encrypts disk sectors in XTS mode (IEEE 1619), using k1 for
+//the data and k2 for the tweak. Sectors need not be a whole number
+//of blocks; a partial last block is handled by ciphertext stealing.
+type XTS struct {
+	k1, k2 cipher.Block
+}
+
+//NewXTS returns an XTS cipher from a pair of 16-byte block ciphers,
+//or a non-nil error if either has another block size
+func NewXTS(k1, k2 cipher.Block) (*XTS, error) {
+	if k1.BlockSize() != xtsBlockSize || k2.BlockSize() != xtsBlockSize {
+		return nil, errors.New("XTS needs a 16-byte block cipher")
+	}
+	return &XTS{k1, k2}, nil
+}
+
+//mulAlpha multiplies a tweak by x in GF(2^128), in XTS's
+//little-endian byte order
+func mulAlpha(t []byte) {
+	carry := t[15] >> 7
+	for i := 15; i > 0; i-- {
+		t[i] = t[i]<<1 | t[i-1]>>7
+	}
+	t[0] = t[0]<<1 ^ carry*0x87
+}
+
+//tweaks returns the tweak for each block, including a partial
+//last block, of a sector of the given length
+func (x *XTS) tweaks(sector uint64, length int) [][]byte {
+	t := make([]byte, xtsBlockSize)
+	binary.LittleEndian.PutUint64(t, sector)
+	x.k2.Encrypt(t, t)
+	n := (length + xtsBlockSize - 1) / xtsBlockSize
+	ts := make([][]byte, n)
+	for i := range ts {
+		ts[i] = append([]byte{}, t...)
+		mulAlpha(t)
+	}
+	return ts
+}
+
+//cryptBlock encrypts or decrypts a single block under a tweak
+func (x *XTS) cryptBlock(dst, src, tweak []byte, decrypt bool) {
+	tmp := make([]byte, xtsBlockSize)
+	xorInto(tmp, src, tweak)
+	if decrypt {
+		x.k1.Decrypt(tmp, tmp)
+	} else {
+		x.k1.Encrypt(tmp, tmp)
+	}
+	xorInto(dst[:xtsBlockSize], tmp, tweak)
+}
+
+//crypt encrypts or decrypts a sector
+func (x *XTS) crypt(dst, src []byte, sector uint64, decrypt bool) {
+	if len(src) < xtsBlockSize {
+		panic("XTS sector must be at least one block")
+	}
+	if len(dst) < len(src) {
+		panic("Output smaller than input")
+	}
+	ts := x.tweaks(sector, len(src))
+	full := len(src) / xtsBlockSize
+	rem := len(src) % xtsBlockSize
+	if rem != 0 {
+		//the last full block takes part in ciphertext stealing
+		full--
+	}
+	for i := 0; i < full; i++ {
+		x.cryptBlock(dst[i*xtsBlockSize:], src[i*xtsBlockSize:], ts[i], decrypt)
+	}
+	if rem == 0 {
+		return
+	}
+
+	//the last full block uses the final tweak when decrypting,
+	//and the partial block borrows the rest of its output
+	last := full * xtsBlockSize
+	first, second := ts[full], ts[full+1]
+	if decrypt {
+		first, second = second, first
+	}
+	cc := make([]byte, xtsBlockSize)
+	x.cryptBlock(cc, src[last:], first, decrypt)
+	pp := make([]byte, xtsBlockSize)
+	copy(pp, src[last+xtsBlockSize:])
+	copy(pp[rem:], cc[rem:])
+	copy(dst[last+xtsBlockSize:], cc[:rem])
+	x.cryptBlock(dst[last:], pp, second, decrypt)
+}
+
+//EncryptSector encrypts a sector of at least 16 bytes
+//with the given sector number
+func (x *XTS) EncryptSector(dst, src []byte, sector uint64) {
+	x.crypt(dst, src, sector, false)
+}
+
+//DecryptSector decrypts a sector of at least 16 bytes
+//with the given sector number
+func (x *XTS) DecryptSector(dst, src []byte, sector uint64) {
+	x.crypt(dst, src, sector, true)
+}
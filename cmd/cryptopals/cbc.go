@@ -0,0 +1,77 @@
+//This file contains the CBC padding oracle subcommand.
+
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+
+	"github.com/alanese/cryptopals/attacks"
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+type paddingOracleResult struct {
+	IV         hexBytes `json:"iv"`
+	Ciphertext hexBytes `json:"ciphertext"`
+	Plaintext  hexBytes `json:"plaintext"`
+}
+
+func (r paddingOracleResult) text() string {
+	return fmt.Sprintf("iv: %x\nciphertext: %x\nplaintext: %q\n", []byte(r.IV), []byte(r.Ciphertext), r.Plaintext)
+}
+
+//runPaddingOracle decrypts a ciphertext, or forges one for a chosen
+//plaintext, against a CBC padding oracle. The oracle is a remote server
+//if -url is given; otherwise the challenge 17 oracle is attacked with
+//a random key and one of its own ciphertexts.
+func runPaddingOracle(args []string) error {
+	fs := flag.NewFlagSet("padding-oracle", flag.ExitOnError)
+	in := addInputFlags(fs, "hex")
+	url := fs.String("url", "", "oracle URL `format`, with %x for the IV then %x for the ciphertext")
+	ivHex := fs.String("iv", "", "IV in hex")
+	encrypt := fs.String("encrypt", "", "forge a ciphertext for this `plaintext` instead of decrypting")
+	blockSize := fs.Int("block", 16, "cipher block size when forging")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	var oracle oracles.CBCPaddingOracle
+	var ctext, iv []byte
+	if *url != "" {
+		oracle = oracles.HTTPPaddingOracle{URL: *url}
+	} else {
+		key := bytesutil.GenerateRandomByteSlice(16)
+		iv = bytesutil.GenerateRandomByteSlice(16)
+		ctext = oracles.Challenge17Encrypt(key, iv)
+		oracle = oracles.C17Oracle{Key: key}
+	}
+
+	if *encrypt != "" {
+		ptext := []byte(*encrypt)
+		ctext, iv := attacks.CBCPaddingOracleEncrypt(ptext, *blockSize, oracle)
+		return printResult(paddingOracleResult{iv, ctext, ptext}, *asJSON)
+	}
+
+	if *url != "" {
+		var err error
+		if ctext, err = in.bytes(); err != nil {
+			return err
+		}
+		if iv, err = hex.DecodeString(*ivHex); err != nil {
+			return err
+		}
+		if len(iv) == 0 {
+			return fmt.Errorf("-iv is required with -url")
+		}
+	}
+	ptext, err := attacks.CBCPaddingOracleDecrypt(ctext, iv, oracle)
+	if err != nil {
+		return err
+	}
+	if stripped, err := ciphers.StripPKCS7Padding(ptext, len(iv)); err == nil {
+		ptext = stripped
+	}
+	return printResult(paddingOracleResult{iv, ctext, ptext}, *asJSON)
+}
@@ -0,0 +1,145 @@
+//This file contains the interactive crib-dragging subcommand.
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alanese/cryptopals/attacks"
+)
+
+const cribDragHelp = `commands:
+  drag <text>                 slide text across both messages and list the best fragments
+  place <msg> <offset> <text> record that message 0 or 1 has text at offset
+  show                        print both messages and the keystream as recovered so far
+  undo                        revert the last place
+  help                        print this message
+  quit                        exit
+Text runs to the end of the line, spaces included; wrap it in double
+quotes to use Go escapes such as \n or \x00.
+`
+
+//cribArg parses the text argument of a crib-dragging command
+func cribArg(s string) ([]byte, error) {
+	if strings.HasPrefix(s, `"`) {
+		u, err := strconv.Unquote(s)
+		return []byte(u), err
+	}
+	return []byte(s), nil
+}
+
+//runCribDrag runs an interactive crib-dragging session on two
+//ciphertexts encrypted under the same keystream
+func runCribDrag(args []string) error {
+	fs := flag.NewFlagSet("crib-drag", flag.ExitOnError)
+	in := addInputFlags(fs, "hex")
+	corpus := fs.String("corpus", "", "sample text `file` to build the language model from (default: built-in English)")
+	scorerName := fs.String("scorer", "trigram", "fragment scorer: angle, chi2, unigram, bigram or trigram")
+	top := fs.Int("top", 10, "number of fragments to list for each drag")
+	fs.Parse(args)
+
+	scorer, err := newScorer(*scorerName, *corpus)
+	if err != nil {
+		return err
+	}
+	ctexts, err := in.lines()
+	if err != nil {
+		return err
+	}
+	if len(ctexts) != 2 {
+		return fmt.Errorf("Need exactly two ciphertexts, got %v", len(ctexts))
+	}
+	session := attacks.NewCribDragSession(ctexts[0], ctexts[1], scorer)
+	return cribDragREPL(session, os.Stdin, os.Stdout, *top)
+}
+
+//cribDragREPL reads commands from r and writes results to w
+//until quit or end of input
+func cribDragREPL(session *attacks.CribDragSession, r io.Reader, w io.Writer, top int) error {
+	fmt.Fprint(w, cribDragHelp)
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprint(w, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return scanner.Err()
+		}
+		line := scanner.Text()
+		cmd, rest := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			cmd, rest = line[:i], line[i+1:]
+		}
+
+		switch cmd {
+		case "drag":
+			crib, err := cribArg(rest)
+			if err != nil || len(crib) == 0 {
+				fmt.Fprintln(w, "usage: drag <text>")
+				continue
+			}
+			matches := session.Drag(crib)
+			for i, m := range matches {
+				if i >= top {
+					break
+				}
+				fmt.Fprintf(w, "%4v  %-8.3f %q\n", m.Offset, m.Score, m.Fragment)
+			}
+		case "place":
+			parts := strings.SplitN(rest, " ", 3)
+			if len(parts) != 3 {
+				fmt.Fprintln(w, "usage: place <msg> <offset> <text>")
+				continue
+			}
+			msg, err1 := strconv.Atoi(parts[0])
+			offset, err2 := strconv.Atoi(parts[1])
+			text, err3 := cribArg(parts[2])
+			if err1 != nil || err2 != nil || err3 != nil {
+				fmt.Fprintln(w, "usage: place <msg> <offset> <text>")
+				continue
+			}
+			if err := session.Place(msg, offset, text); err != nil {
+				fmt.Fprintln(w, err)
+				continue
+			}
+			printSession(session, w)
+		case "show":
+			printSession(session, w)
+		case "undo":
+			if !session.Undo() {
+				fmt.Fprintln(w, "nothing to undo")
+				continue
+			}
+			printSession(session, w)
+		case "help":
+			fmt.Fprint(w, cribDragHelp)
+		case "quit", "exit":
+			return nil
+		case "":
+		default:
+			fmt.Fprintf(w, "unknown command %q; try help\n", cmd)
+		}
+	}
+}
+
+//printSession prints both messages and the keystream as recovered
+//so far, with unknown bytes shown as underscores
+func printSession(session *attacks.CribDragSession, w io.Writer) {
+	for msg := 0; msg < 2; msg++ {
+		fmt.Fprintf(w, "msg %v: %q\n", msg, session.Plaintext(msg, '_'))
+	}
+	ks, known := session.Keystream()
+	hexKs := []byte(hex.EncodeToString(ks))
+	for i, k := range known {
+		if !k {
+			hexKs[2*i], hexKs[2*i+1] = '_', '_'
+		}
+	}
+	fmt.Fprintf(w, "keystream: %s\n", hexKs)
+}
@@ -0,0 +1,77 @@
+//This file contains the fixed-nonce CTR subcommand.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alanese/cryptopals/attacks"
+)
+
+type fixedNonceResult struct {
+	Keystream  hexBytes `json:"keystream"`
+	Plaintexts []string `json:"plaintexts"`
+}
+
+func (r fixedNonceResult) text() string {
+	s := fmt.Sprintf("keystream: %x\n", []byte(r.Keystream))
+	for _, p := range r.Plaintexts {
+		s += fmt.Sprintf("%q\n", p)
+	}
+	return s
+}
+
+//cribList collects -crib flags of the form line:offset:text
+type cribList []attacks.Crib
+
+func (c *cribList) String() string {
+	return fmt.Sprint(*c)
+}
+
+func (c *cribList) Set(s string) error {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return fmt.Errorf("Crib must be line:offset:text")
+	}
+	line, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return err
+	}
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return err
+	}
+	*c = append(*c, attacks.Crib{Line: line, Offset: offset, Text: []byte(parts[2])})
+	return nil
+}
+
+//runFixedNonce recovers the keystream shared by several stream
+//cipher ciphertexts, one per input line
+func runFixedNonce(args []string) error {
+	fs := flag.NewFlagSet("fixed-nonce", flag.ExitOnError)
+	in := addInputFlags(fs, "base64")
+	corpus := fs.String("corpus", "", "sample text `file` to build the language model from (default: built-in English)")
+	scorerName := fs.String("scorer", "trigram", "plaintext scorer: angle, chi2, unigram, bigram or trigram")
+	var cribs cribList
+	fs.Var(&cribs, "crib", "known plaintext as `line:offset:text`; may be repeated")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	scorer, err := newScorer(*scorerName, *corpus)
+	if err != nil {
+		return err
+	}
+	ctexts, err := in.lines()
+	if err != nil {
+		return err
+	}
+	keystream, ptexts := attacks.BreakFixedNonceCTR(ctexts, scorer, cribs...)
+	res := fixedNonceResult{Keystream: keystream}
+	for _, p := range ptexts {
+		res.Plaintexts = append(res.Plaintexts, string(p))
+	}
+	return printResult(res, *asJSON)
+}
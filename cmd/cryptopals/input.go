@@ -0,0 +1,147 @@
+//This file contains the input and output helpers shared by
+//the subcommands.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/alanese/cryptopals/bytesutil"
+)
+
+//input describes a subcommand's main input: either a literal
+//value or a file, in one of several encodings
+type input struct {
+	value    string
+	file     string
+	encoding string
+}
+
+//addInputFlags registers the -in, -file and -enc flags on fs
+func addInputFlags(fs *flag.FlagSet, defaultEncoding string) *input {
+	in := &input{}
+	fs.StringVar(&in.value, "in", "", "input value")
+	fs.StringVar(&in.file, "file", "", "read input from `file`")
+	fs.StringVar(&in.encoding, "enc", defaultEncoding, "input encoding: hex, base64 or raw")
+	return in
+}
+
+//decode decodes s according to the input encoding
+func (in *input) decode(s []byte) ([]byte, error) {
+	switch in.encoding {
+	case "hex":
+		return hex.DecodeString(strings.Join(strings.Fields(string(s)), ""))
+	case "base64":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(s)), ""))
+	case "raw":
+		return s, nil
+	}
+	return nil, fmt.Errorf("Unknown encoding %q", in.encoding)
+}
+
+//bytes reads and decodes the whole input
+func (in *input) bytes() ([]byte, error) {
+	switch {
+	case in.file != "" && in.encoding == "base64":
+		return bytesutil.DecodeFileBase64(in.file)
+	case in.file != "":
+		b, err := ioutil.ReadFile(in.file)
+		if err != nil {
+			return nil, err
+		}
+		return in.decode(b)
+	case in.value != "":
+		return in.decode([]byte(in.value))
+	}
+	return nil, fmt.Errorf("No input given; use -in or -file")
+}
+
+//lines reads the input as a list of lines, decoding each line
+//separately and skipping blank lines. A literal value is split on
+//commas instead.
+func (in *input) lines() ([][]byte, error) {
+	var raw [][]byte
+	switch {
+	case in.file != "":
+		var err error
+		raw, err = bytesutil.LinesFromFile(in.file)
+		if err != nil {
+			return nil, err
+		}
+	case in.value != "":
+		for _, s := range strings.Split(in.value, ",") {
+			raw = append(raw, []byte(s))
+		}
+	default:
+		return nil, fmt.Errorf("No input given; use -in or -file")
+	}
+
+	lines := [][]byte{}
+	for _, l := range raw {
+		if strings.TrimSpace(string(l)) == "" {
+			continue
+		}
+		decoded, err := in.decode(l)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, decoded)
+	}
+	return lines, nil
+}
+
+//parseHexList parses a comma-separated list of hex strings
+func parseHexList(s string) ([][]byte, error) {
+	list := [][]byte{}
+	for _, item := range strings.Split(s, ",") {
+		b, err := hex.DecodeString(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, b)
+	}
+	return list, nil
+}
+
+//parseHexInt parses a hex string as a big integer
+func parseHexInt(s string) (*big.Int, error) {
+	n, ok := big.NewInt(0).SetString(strings.TrimPrefix(strings.TrimSpace(s), "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("Invalid hex integer %q", s)
+	}
+	return n, nil
+}
+
+//result is the output of a subcommand
+type result interface {
+	text() string
+}
+
+//printResult writes r to stdout, either as indented JSON or
+//as human-readable text
+func printResult(r result, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	fmt.Print(r.text())
+	return nil
+}
+
+//hexBytes is a byte slice which is written to JSON as a hex
+//string rather than base64
+type hexBytes []byte
+
+//MarshalJSON encodes b as a hex string
+func (b hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
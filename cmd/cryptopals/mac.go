@@ -0,0 +1,79 @@
+//This file contains the length extension subcommands.
+
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+
+	"github.com/alanese/cryptopals/attacks"
+	"github.com/alanese/cryptopals/hashes"
+)
+
+type forgery struct {
+	SecretLength int      `json:"secret_length"`
+	Message      hexBytes `json:"message"`
+	Digest       hexBytes `json:"digest"`
+}
+
+type forgeResult struct {
+	Forgeries []forgery `json:"forgeries"`
+}
+
+func (r forgeResult) text() string {
+	s := ""
+	for _, f := range r.Forgeries {
+		s += fmt.Sprintf("secret length %v:\n  message: %x\n  digest:  %x\n", f.SecretLength, []byte(f.Message), []byte(f.Digest))
+	}
+	return s
+}
+
+//runForgeMAC extends a secret-prefix MAC of any Merkle-Damgard hash.
+//Since the secret length is unknown, one forgery is printed per
+//candidate length.
+func runForgeMAC(args []string) error {
+	return forgeMAC("forge-mac", "sha1", args)
+}
+
+//runForgeSHA1MAC is forge-mac for SHA-1, under its old name
+func runForgeSHA1MAC(args []string) error {
+	return forgeMAC("forge-sha1-mac", "sha1", args)
+}
+
+//forgeMAC runs the MAC-forging command name, defaulting to the hash
+//named defaultHash
+func forgeMAC(name, defaultHash string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	in := addInputFlags(fs, "raw")
+	hashName := fs.String("hash", defaultHash, "hash: md4, md5, sha1, sha256 or sha512")
+	digestHex := fs.String("digest", "", "original MAC in hex (required)")
+	appendStr := fs.String("append", ";admin=true", "`text` to append to the message")
+	minLen := fs.Int("min-secret", 0, "smallest secret length to try")
+	maxLen := fs.Int("max-secret", 32, "largest secret length to try")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	h, ok := hashes.MDHashes[*hashName]
+	if !ok {
+		return fmt.Errorf("Unknown hash %q", *hashName)
+	}
+	message, err := in.bytes()
+	if err != nil {
+		return err
+	}
+	digest, err := hex.DecodeString(*digestHex)
+	if err != nil {
+		return err
+	}
+
+	forgeries, err := attacks.ExtendMACRange(h, message, digest, []byte(*appendStr), *minLen, *maxLen)
+	if err != nil {
+		return err
+	}
+	res := forgeResult{}
+	for _, f := range forgeries {
+		res.Forgeries = append(res.Forgeries, forgery{f.SecretLen, f.Message, f.Digest})
+	}
+	return printResult(res, *asJSON)
+}
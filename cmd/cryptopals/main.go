@@ -0,0 +1,65 @@
+//This file contains the command-line driver. Each attack is
+//exposed as a subcommand; run with no arguments for a list.
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+)
+
+//command is a single subcommand of the driver
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"break-xor", "break single-byte or repeating-key XOR", runBreakXor},
+	{"detect-ecb", "find AES-ECB ciphertexts among a file of lines", runDetectECB},
+	{"padding-oracle", "decrypt or forge CBC ciphertexts with a padding oracle", runPaddingOracle},
+	{"fixed-nonce", "recover plaintexts encrypted under a reused stream cipher keystream", runFixedNonce},
+	{"crib-drag", "interactively crib-drag two messages sharing a stream cipher keystream", runCribDrag},
+	{"clone-mt", "clone an MT19937 generator from 624 outputs and predict the rest", runCloneMT},
+	{"seed-search", "find the seed of a clock-seeded PRNG from its outputs", runSeedSearch},
+	{"audit-tokens", "test whether tokens came from a predictable generator and predict the next", runAuditTokens},
+	{"forge-mac", "extend a secret-prefix MD4, MD5, SHA-1, SHA-256 or SHA-512 MAC", runForgeMAC},
+	{"forge-sha1-mac", "extend a secret-prefix SHA-1 MAC (forge-mac -hash sha1)", runForgeSHA1MAC},
+	{"hmac-timing", "recover a MAC (HMAC, prefix, suffix or envelope, any hash) through a timing leak", runHMACTiming},
+	{"rsa-broadcast", "recover a message sent under several small-exponent RSA keys", runRSABroadcast},
+	{"bleichenbacher", "decrypt an RSA ciphertext with a PKCS#1v1.5 padding oracle", runBleichenbacher},
+	{"rc4-bias", "recover the challenge 56 cookie from RC4 keystream biases", runRC4Bias},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %v <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16v %v\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun %v <command> -h for the flags of a command.\n", os.Args[0])
+}
+
+func main() {
+	//usually need this
+	rand.Seed(time.Now().Unix())
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%v: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
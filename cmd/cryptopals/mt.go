@@ -0,0 +1,58 @@
+//This file contains the MT19937 cloning subcommand.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alanese/cryptopals/attacks"
+)
+
+type cloneResult struct {
+	Predictions []uint32 `json:"predictions"`
+}
+
+func (r cloneResult) text() string {
+	s := ""
+	for _, p := range r.Predictions {
+		s += fmt.Sprintln(p)
+	}
+	return s
+}
+
+//runCloneMT clones an MT19937 generator from at least 624 consecutive
+//outputs, one per line (or comma-separated with -in), and predicts the
+//outputs that follow them
+func runCloneMT(args []string) error {
+	fs := flag.NewFlagSet("clone-mt", flag.ExitOnError)
+	in := addInputFlags(fs, "raw")
+	n := fs.Int("n", 10, "number of outputs to predict")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	lines, err := in.lines()
+	if err != nil {
+		return err
+	}
+	outputs := make([]uint32, len(lines))
+	for i, l := range lines {
+		v, err := strconv.ParseUint(strings.TrimSpace(string(l)), 0, 32)
+		if err != nil {
+			return fmt.Errorf("Output %v: %v", i, err)
+		}
+		outputs[i] = uint32(v)
+	}
+
+	cloned, err := attacks.CloneTwisterOutputs(outputs)
+	if err != nil {
+		return err
+	}
+	res := cloneResult{make([]uint32, *n)}
+	for i := range res.Predictions {
+		res.Predictions[i] = cloned.Next()
+	}
+	return printResult(res, *asJSON)
+}
@@ -0,0 +1,21 @@
+//This file contains the RC4 bias subcommand.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/alanese/cryptopals/attacks"
+)
+
+//runRC4Bias recovers the challenge 56 cookie. This takes a long
+//time; progress is printed after each byte.
+func runRC4Bias(args []string) error {
+	fs := flag.NewFlagSet("rc4-bias", flag.ExitOnError)
+	fs.Parse(args)
+
+	cookie := attacks.C56GuessCookie()
+	fmt.Printf("%q\n", cookie)
+	return nil
+}
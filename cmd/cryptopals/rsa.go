@@ -0,0 +1,108 @@
+//This file contains the RSA subcommands.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/alanese/cryptopals/attacks"
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/oracles"
+	"github.com/alanese/cryptopals/pubkey"
+)
+
+type plaintextResult struct {
+	Plaintext hexBytes `json:"plaintext"`
+}
+
+func (r plaintextResult) text() string {
+	return fmt.Sprintf("%x\n%q\n", []byte(r.Plaintext), r.Plaintext)
+}
+
+//runRSABroadcast recovers a message encrypted under several RSA keys
+//with the same small exponent, which is taken to be the number of
+//ciphertexts
+func runRSABroadcast(args []string) error {
+	fs := flag.NewFlagSet("rsa-broadcast", flag.ExitOnError)
+	ctextList := fs.String("c", "", "comma-separated hex ciphertexts (required)")
+	modList := fs.String("n", "", "comma-separated hex moduli, in the same order (required)")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	if *ctextList == "" || *modList == "" {
+		return fmt.Errorf("-c and -n are required")
+	}
+	ctexts, err := parseHexList(*ctextList)
+	if err != nil {
+		return err
+	}
+	ns := []*big.Int{}
+	for _, s := range strings.Split(*modList, ",") {
+		n, err := parseHexInt(s)
+		if err != nil {
+			return err
+		}
+		ns = append(ns, n)
+	}
+
+	ptext, err := attacks.RSABroadcastAttack(ctexts, ns)
+	if err != nil {
+		return err
+	}
+	return printResult(plaintextResult{ptext}, *asJSON)
+}
+
+//runBleichenbacher decrypts an RSA ciphertext with a PKCS#1v1.5
+//padding oracle. The oracle is a remote server if -url is given;
+//otherwise a fresh key is generated and the challenge 47 oracle is
+//attacked with an encryption of -msg.
+func runBleichenbacher(args []string) error {
+	fs := flag.NewFlagSet("bleichenbacher", flag.ExitOnError)
+	in := addInputFlags(fs, "hex")
+	url := fs.String("url", "", "oracle URL `format`, with %x for the ciphertext")
+	eHex := fs.String("e", "3", "public exponent in hex")
+	nHex := fs.String("n", "", "modulus in hex (required with -url)")
+	bits := fs.Int("bits", 256, "modulus size in bits when generating a key")
+	msg := fs.String("msg", "kick it, CC", "message to encrypt when generating a key")
+	verbose := fs.Bool("v", false, "print progress")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	var oracle oracles.RSAPaddingOracle
+	var e, n *big.Int
+	var ctext []byte
+	if *url != "" {
+		var err error
+		if e, err = parseHexInt(*eHex); err != nil {
+			return err
+		}
+		if n, err = parseHexInt(*nHex); err != nil {
+			return err
+		}
+		if ctext, err = in.bytes(); err != nil {
+			return err
+		}
+		oracle = oracles.HTTPRSAPaddingOracle{URL: *url}
+	} else {
+		var d *big.Int
+		e, d, n = pubkey.GenerateRSAKeyPair(*bits / 2)
+		padded, err := ciphers.PKCS15Pad([]byte(*msg), len(n.Bytes()))
+		if err != nil {
+			return err
+		}
+		ctext = pubkey.RSAEncrypt(padded, e, n)
+		oracle = oracles.C47Oracle{D: d, N: n}
+	}
+	ctext = bytesutil.PadLeft(ctext, 0x00, len(n.Bytes()))
+
+	ptext := attacks.BleichenbacherAttack(ctext, e, n, oracle, *verbose)
+	ptext = bytesutil.PadLeft(ptext, 0x00, len(n.Bytes()))
+	if stripped, err := ciphers.StripPKCS15Padding(ptext); err == nil {
+		ptext = stripped
+	}
+	return printResult(plaintextResult{ptext}, *asJSON)
+}
@@ -0,0 +1,127 @@
+//This file contains the PRNG seed search subcommand.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alanese/cryptopals/attacks"
+)
+
+type seedMatch struct {
+	Seed   int64 `json:"seed"`
+	Offset int   `json:"offset"`
+}
+
+type seedResult struct {
+	Matches []seedMatch `json:"matches"`
+}
+
+func (r seedResult) text() string {
+	if len(r.Matches) == 0 {
+		return "No seed found\n"
+	}
+	s := ""
+	for _, m := range r.Matches {
+		s += fmt.Sprintf("seed %v, offset %v\n", m.Seed, m.Offset)
+	}
+	return s
+}
+
+//seedGenerators are the generators by their -gen names
+var seedGenerators = map[string]attacks.SeedGenerator{
+	"mt19937":   attacks.SeedMT19937,
+	"math/rand": attacks.SeedGoMathRand,
+	"glibc":     attacks.SeedGlibc,
+	"java":      attacks.SeedJava,
+}
+
+//seedUnits are the clock units by their -unit names
+var seedUnits = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"ns": time.Nanosecond,
+}
+
+//parseSeedObservation parses an output line: a value, a value and
+//mask as value/mask, or ? for an output not seen
+func parseSeedObservation(line string) (attacks.SeedObservation, error) {
+	line = strings.TrimSpace(line)
+	if line == "?" {
+		return attacks.SeedObservation{}, nil
+	}
+	value, mask, partial := strings.Cut(line, "/")
+	v, err := strconv.ParseUint(value, 0, 64)
+	if err != nil {
+		return attacks.SeedObservation{}, err
+	}
+	o := attacks.SeedObservation{Mask: ^uint64(0), Value: v}
+	if partial {
+		if o.Mask, err = strconv.ParseUint(mask, 0, 64); err != nil {
+			return attacks.SeedObservation{}, err
+		}
+	}
+	return o, nil
+}
+
+//runSeedSearch searches for the seed of a PRNG from consecutive
+//outputs, one per line (or comma-separated with -in), over a range of
+//seeds or a window of clock values before now
+func runSeedSearch(args []string) error {
+	fs := flag.NewFlagSet("seed-search", flag.ExitOnError)
+	in := addInputFlags(fs, "raw")
+	gen := fs.String("gen", "mt19937", "generator: mt19937, math/rand, glibc or java")
+	from := fs.Int64("from", 0, "first seed to try, if -to is set")
+	to := fs.Int64("to", 0, "seed to stop before; if unset, search a window of clock values")
+	since := fs.Duration("since", time.Hour, "with no -to, how far back from now to search")
+	unit := fs.String("unit", "s", "with no -to, the clock unit of the seed: s, ms or ns")
+	maxOffset := fs.Int("max-offset", 0, "how many outputs may come before the observed ones")
+	workers := fs.Int("workers", 0, "goroutines to search with (default one per CPU)")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	g, ok := seedGenerators[*gen]
+	if !ok {
+		return fmt.Errorf("Unknown generator %q", *gen)
+	}
+	lines, err := in.lines()
+	if err != nil {
+		return err
+	}
+	search := attacks.SeedSearch{
+		Generator: g,
+		Observed:  make([]attacks.SeedObservation, len(lines)),
+		From:      *from,
+		To:        *to,
+		MaxOffset: *maxOffset,
+		Workers:   *workers,
+	}
+	for i, l := range lines {
+		if search.Observed[i], err = parseSeedObservation(string(l)); err != nil {
+			return fmt.Errorf("Output %v: %v", i, err)
+		}
+	}
+	if *to == 0 {
+		u, ok := seedUnits[*unit]
+		if !ok {
+			return errors.New("Unit must be s, ms or ns")
+		}
+		now := time.Now()
+		search.From, search.To = attacks.TimeSeeds(now.Add(-*since), now, u)
+	}
+
+	matches, err := search.Run()
+	if err != nil {
+		return err
+	}
+	res := seedResult{[]seedMatch{}}
+	for _, m := range matches {
+		res.Matches = append(res.Matches, seedMatch{m.Seed, m.Offset})
+	}
+	return printResult(res, *asJSON)
+}
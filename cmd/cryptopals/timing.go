@@ -0,0 +1,117 @@
+//This file contains the HMAC timing attack subcommand.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/alanese/cryptopals/attacks"
+	"github.com/alanese/cryptopals/hashes"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+type timingResult struct {
+	Message    string    `json:"message"`
+	MAC        hexBytes  `json:"mac"`
+	Confidence []float64 `json:"confidence,omitempty"`
+	Queries    int       `json:"queries,omitempty"`
+}
+
+func (r timingResult) text() string {
+	s := fmt.Sprintf("%x\n", []byte(r.MAC))
+	if r.Confidence != nil {
+		s += fmt.Sprintf("%v queries\n", r.Queries)
+		for i, c := range r.Confidence {
+			s += fmt.Sprintf("byte %2v: %02x confidence %.4f\n", i, r.MAC[i], c)
+		}
+	}
+	return s
+}
+
+//newMAC returns the MAC construction named name over h under key
+func newMAC(name string, h *hashes.MDHash, key []byte) (hashes.MAC, error) {
+	switch name {
+	case "hmac":
+		return hashes.HMAC{New: h.NewHash, Key: key}, nil
+	case "prefix":
+		return hashes.PrefixMAC{New: h.NewHash, Key: key}, nil
+	case "suffix":
+		return hashes.SuffixMAC{New: h.NewHash, Key: key}, nil
+	case "envelope":
+		return hashes.EnvelopeMAC{New: h.NewHash, Key: key}, nil
+	}
+	return nil, fmt.Errorf("Unknown MAC construction %q", name)
+}
+
+//runHMACTiming recovers the MAC of a message through a timing leak.
+//The oracle is a remote server if -url is given; otherwise checks are
+//simulated in-process, which is fast and, for a fixed seed, repeatable.
+//The simulated server may use any MAC construction, and against a
+//server -hash, -mac and -truncate give the length of its MACs.
+func runHMACTiming(args []string) error {
+	fs := flag.NewFlagSet("hmac-timing", flag.ExitOnError)
+	url := fs.String("url", "", "server URL `format`, with %v for the message then %X for the MAC")
+	msg := fs.String("msg", "foo", "message to find the MAC of")
+	key := fs.String("key", "THIS IS A SECRET DON'T TELL ANYONE", "MAC key of the simulated server")
+	delay := fs.Duration("delay", 2*time.Millisecond, "simulated delay per matching byte")
+	overhead := fs.Duration("overhead", 500*time.Microsecond, "simulated network overhead")
+	jitter := fs.Duration("jitter", 100*time.Microsecond, "standard deviation of simulated noise")
+	seed := fs.Int64("seed", 1, "seed for the simulated noise")
+	hashName := fs.String("hash", "sha1", "hash of the MAC: md4, md5, sha1, sha256 or sha512")
+	construction := fs.String("mac", "hmac", "MAC construction: hmac, prefix, suffix or envelope")
+	truncate := fs.Int("truncate", 0, "bytes the MAC is truncated to, or 0 for the whole MAC")
+	strategy := fs.String("strategy", "robust", "guessing strategy: robust, or simple for the original challenge 31 attack")
+	stat := fs.String("stat", "trimmed", "robust strategy statistic: trimmed or median")
+	verbose := fs.Bool("v", false, "print progress")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	h, ok := hashes.MDHashes[*hashName]
+	if !ok {
+		return fmt.Errorf("Unknown hash %q", *hashName)
+	}
+	mac, err := newMAC(*construction, h, []byte(*key))
+	if err != nil {
+		return err
+	}
+	if *truncate > 0 {
+		mac = hashes.TruncatedMAC{MAC: mac, Length: *truncate}
+	}
+
+	var oracle oracles.TimingOracle
+	if *url != "" {
+		oracle = oracles.HTTPTimingOracle{URL: *url}
+	} else {
+		oracle = oracles.NewSimulatedMACTimingOracle(mac, *delay, *overhead, *jitter, *seed)
+	}
+
+	switch *strategy {
+	case "simple":
+		if mac.Size() != 20 {
+			return fmt.Errorf("The simple strategy needs a 20-byte MAC")
+		}
+		mac := attacks.C31BreakHash(oracle, []byte(*msg), *verbose)
+		return printResult(timingResult{Message: *msg, MAC: mac}, *asJSON)
+	case "robust":
+	default:
+		return fmt.Errorf("Unknown strategy %q", *strategy)
+	}
+
+	cfg := attacks.DefaultTimingAttackConfig
+	cfg.MACLength = mac.Size()
+	switch *stat {
+	case "trimmed":
+		cfg.Statistic = attacks.TrimmedMean
+	case "median":
+		cfg.Statistic = attacks.Median
+	default:
+		return fmt.Errorf("Unknown statistic %q", *stat)
+	}
+	res, err := attacks.BreakHMACTiming(oracle, []byte(*msg), cfg, *verbose)
+	if err != nil {
+		return err
+	}
+	return printResult(timingResult{*msg, res.MAC, res.Confidence, res.Queries}, *asJSON)
+}
@@ -0,0 +1,163 @@
+//This file contains the set 1 subcommands: breaking XOR
+//ciphers and detecting ECB.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/alanese/cryptopals/attacks"
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/scoring"
+)
+
+type xorResult struct {
+	Line      int      `json:"line"`
+	Key       hexBytes `json:"key"`
+	Plaintext string   `json:"plaintext"`
+}
+
+func (r xorResult) text() string {
+	s := ""
+	if r.Line >= 0 {
+		s += fmt.Sprintf("line: %v\n", r.Line)
+	}
+	return s + fmt.Sprintf("key: %x\n%v\n", []byte(r.Key), r.Plaintext)
+}
+
+//runBreakXor breaks single-byte or repeating-key XOR. With -lines,
+//every line of the input is tried as a single-byte XOR ciphertext
+//and the best-scoring line is reported.
+func runBreakXor(args []string) error {
+	fs := flag.NewFlagSet("break-xor", flag.ExitOnError)
+	in := addInputFlags(fs, "hex")
+	corpus := fs.String("corpus", "", "sample text `file` to build the language model from (default: built-in English)")
+	scorerName := fs.String("scorer", "trigram", "plaintext scorer: angle, chi2, unigram, bigram or trigram")
+	single := fs.Bool("single", false, "input is single-byte XOR rather than repeating-key XOR")
+	lines := fs.Bool("lines", false, "find the single-byte XOR ciphertext among the input lines")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	scorer, err := newScorer(*scorerName, *corpus)
+	if err != nil {
+		return err
+	}
+
+	if *lines {
+		ctexts, err := in.lines()
+		if err != nil {
+			return err
+		}
+		ptexts := make([][]byte, len(ctexts))
+		for i, c := range ctexts {
+			ptexts[i] = attacks.BreakSingleByteXor(c, scorer)
+		}
+		best, _ := scoring.Best(scorer, ptexts)
+		if best < 0 {
+			return fmt.Errorf("No ciphertexts in input")
+		}
+		key := hexBytes{ctexts[best][0] ^ ptexts[best][0]}
+		return printResult(xorResult{best, key, string(ptexts[best])}, *asJSON)
+	}
+
+	ctext, err := in.bytes()
+	if err != nil {
+		return err
+	}
+	if len(ctext) == 0 {
+		return fmt.Errorf("Empty ciphertext")
+	}
+	var ptext []byte
+	keyLen := 1
+	if *single {
+		ptext = attacks.BreakSingleByteXor(ctext, scorer)
+	} else {
+		maxLen := 40
+		if len(ctext)/2 < maxLen {
+			maxLen = len(ctext) / 2
+		}
+		if maxLen < 2 {
+			return fmt.Errorf("Ciphertext too short")
+		}
+		keyLen = attacks.GuessRepeatedXorKeyLen(ctext, 2, maxLen)
+		ptext = attacks.BreakKnownLenRepeatedXor(ctext, keyLen, scorer)
+	}
+	key, _ := bytesutil.XorBufs(ctext[:keyLen], ptext[:keyLen])
+	return printResult(xorResult{-1, shortestPeriod(key), string(ptext)}, *asJSON)
+}
+
+//newScorer creates the named scorer over the model built from
+//corpusFile, or over the built-in English model if corpusFile is empty
+func newScorer(name, corpusFile string) (scoring.Scorer, error) {
+	model := scoring.English()
+	if corpusFile != "" {
+		var err error
+		if model, err = scoring.ModelFromFile(corpusFile); err != nil {
+			return nil, err
+		}
+	}
+	switch name {
+	case "angle":
+		return scoring.AngleScorer{Dist: model.Counts()}, nil
+	case "chi2":
+		return scoring.ChiSquaredScorer{Model: model}, nil
+	case "unigram":
+		return scoring.NGramScorer{Model: model, N: 1}, nil
+	case "bigram":
+		return scoring.NGramScorer{Model: model, N: 2}, nil
+	case "trigram":
+		return scoring.NGramScorer{Model: model, N: 3}, nil
+	}
+	return nil, fmt.Errorf("Unknown scorer %q", name)
+}
+
+//shortestPeriod returns the shortest prefix of key which repeats
+//to form key. The key length guesser sometimes picks a multiple of
+//the true key length.
+func shortestPeriod(key []byte) []byte {
+	for p := 1; p < len(key); p++ {
+		if len(key)%p == 0 && bytes.Equal(key[p:], key[:len(key)-p]) {
+			return key[:p]
+		}
+	}
+	return key
+}
+
+type ecbResult struct {
+	Lines []int `json:"lines"`
+}
+
+func (r ecbResult) text() string {
+	if len(r.Lines) == 0 {
+		return "no ECB ciphertexts found\n"
+	}
+	s := []string{}
+	for _, l := range r.Lines {
+		s = append(s, fmt.Sprint(l))
+	}
+	return "ECB detected on line(s) " + strings.Join(s, ", ") + "\n"
+}
+
+//runDetectECB reports which lines of the input contain a repeated
+//16-byte block
+func runDetectECB(args []string) error {
+	fs := flag.NewFlagSet("detect-ecb", flag.ExitOnError)
+	in := addInputFlags(fs, "hex")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+
+	ctexts, err := in.lines()
+	if err != nil {
+		return err
+	}
+	res := ecbResult{Lines: []int{}}
+	for i, c := range ctexts {
+		if attacks.DetectAESECB(c) {
+			res.Lines = append(res.Lines, i)
+		}
+	}
+	return printResult(res, *asJSON)
+}
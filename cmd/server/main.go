@@ -0,0 +1,92 @@
+//This file contains a configurable server running the
+//network-facing challenge services. Run with -h for flags.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/pubkey"
+	"github.com/alanese/cryptopals/services"
+)
+
+//service is anything which can add its endpoints to a mux
+type service interface {
+	Register(mux *http.ServeMux)
+}
+
+//readUsers reads a user database of username:password lines,
+//skipping blank lines and lines starting with #
+func readUsers(fname string) (map[string]string, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	users := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%v:%v: expected username:password", fname, n)
+		}
+		users[parts[0]] = parts[1]
+	}
+	return users, scanner.Err()
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "listen `address`")
+	enable := flag.String("enable", "hmac,srp,padding,rsa", "comma-separated services to enable")
+	delay := flag.Duration("delay", 2*time.Millisecond, "HMAC comparison delay per matching byte")
+	secret := flag.String("secret", "THIS IS A SECRET DON'T TELL ANYONE", "HMAC secret key")
+	usersFile := flag.String("users", "", "SRP user database `file` of username:password lines (default: bob:secretpassword123)")
+	rsaBits := flag.Int("rsa-bits", 768, "RSA modulus size in bits")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	for _, name := range strings.Split(*enable, ",") {
+		var s service
+		switch strings.TrimSpace(name) {
+		case "hmac":
+			s = &services.HMACService{Secret: []byte(*secret), ByteDelay: *delay}
+			log.Printf("hmac: /hmac?file=...&signature=..., %v per byte", *delay)
+		case "srp":
+			users := map[string]string{"bob": "secretpassword123"}
+			if *usersFile != "" {
+				var err error
+				if users, err = readUsers(*usersFile); err != nil {
+					log.Fatal(err)
+				}
+			}
+			s = services.NewSRPService(users)
+			log.Printf("srp: /getB, /validate, %v users", len(users))
+		case "padding":
+			s = &services.PaddingService{Key: bytesutil.GenerateSecureRandomByteSlice(16)}
+			log.Printf("padding: /padding?iv=...&ctext=..., /padding/sample")
+		case "rsa":
+			e, d, n := pubkey.GenerateRSAKeyPair(*rsaBits / 2)
+			s = &services.RSAService{E: e, D: d, N: n}
+			log.Printf("rsa: /rsa?ctext=..., /rsa/key (e=%x, n=%x)", e, n)
+		default:
+			log.Fatalf("unknown service %q", name)
+		}
+		s.Register(mux)
+	}
+
+	log.Printf("listening on %v", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
@@ -0,0 +1,253 @@
+package hashes
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+//md4Block is MD4's compression function
+func md4Block(state []uint64, block []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	a, b, c, d := uint32(state[0]), uint32(state[1]), uint32(state[2]), uint32(state[3])
+	for i := 0; i < 16; i += 4 {
+		a = MD4Phi0(a, b, c, d, x[i], 3)
+		d = MD4Phi0(d, a, b, c, x[i+1], 7)
+		c = MD4Phi0(c, d, a, b, x[i+2], 11)
+		b = MD4Phi0(b, c, d, a, x[i+3], 19)
+	}
+	for i := 0; i < 4; i++ {
+		a = MD4Phi1(a, b, c, d, x[i], 3)
+		d = MD4Phi1(d, a, b, c, x[i+4], 5)
+		c = MD4Phi1(c, d, a, b, x[i+8], 9)
+		b = MD4Phi1(b, c, d, a, x[i+12], 13)
+	}
+	for _, i := range []int{0, 2, 1, 3} {
+		a = MD4Phi2(a, b, c, d, x[i], 3)
+		d = MD4Phi2(d, a, b, c, x[i+8], 9)
+		c = MD4Phi2(c, d, a, b, x[i+4], 11)
+		b = MD4Phi2(b, c, d, a, x[i+12], 15)
+	}
+	state[0] = uint64(uint32(state[0]) + a)
+	state[1] = uint64(uint32(state[1]) + b)
+	state[2] = uint64(uint32(state[2]) + c)
+	state[3] = uint64(uint32(state[3]) + d)
+}
+
+//md5K are MD5's round constants, the integer parts of |sin(i+1)| * 2^32
+var md5K = [64]uint32{
+	0xd76aa478, 0xe8c7b756, 0x242070db, 0xc1bdceee,
+	0xf57c0faf, 0x4787c62a, 0xa8304613, 0xfd469501,
+	0x698098d8, 0x8b44f7af, 0xffff5bb1, 0x895cd7be,
+	0x6b901122, 0xfd987193, 0xa679438e, 0x49b40821,
+	0xf61e2562, 0xc040b340, 0x265e5a51, 0xe9b6c7aa,
+	0xd62f105d, 0x02441453, 0xd8a1e681, 0xe7d3fbc8,
+	0x21e1cde6, 0xc33707d6, 0xf4d50d87, 0x455a14ed,
+	0xa9e3e905, 0xfcefa3f8, 0x676f02d9, 0x8d2a4c8a,
+	0xfffa3942, 0x8771f681, 0x6d9d6122, 0xfde5380c,
+	0xa4beea44, 0x4bdecfa9, 0xf6bb4b60, 0xbebfbc70,
+	0x289b7ec6, 0xeaa127fa, 0xd4ef3085, 0x04881d05,
+	0xd9d4d039, 0xe6db99e5, 0x1fa27cf8, 0xc4ac5665,
+	0xf4292244, 0x432aff97, 0xab9423a7, 0xfc93a039,
+	0x655b59c3, 0x8f0ccc92, 0xffeff47d, 0x85845dd1,
+	0x6fa87e4f, 0xfe2ce6e0, 0xa3014314, 0x4e0811a1,
+	0xf7537e82, 0xbd3af235, 0x2ad7d2bb, 0xeb86d391,
+}
+
+//md5Shift are MD5's rotation amounts, four for each round
+var md5Shift = [4][4]int{{7, 12, 17, 22}, {5, 9, 14, 20}, {4, 11, 16, 23}, {6, 10, 15, 21}}
+
+//md5Block is MD5's compression function
+func md5Block(state []uint64, block []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	a, b, c, d := uint32(state[0]), uint32(state[1]), uint32(state[2]), uint32(state[3])
+	for i := 0; i < 64; i++ {
+		var f uint32
+		var k int
+		switch i / 16 {
+		case 0:
+			f, k = (b&c)|(^b&d), i
+		case 1:
+			f, k = (b&d)|(c&^d), (5*i+1)%16
+		case 2:
+			f, k = b^c^d, (3*i+5)%16
+		case 3:
+			f, k = c^(b|^d), (7*i)%16
+		}
+		f += a + md5K[i] + x[k]
+		a, d, c = d, c, b
+		b += bits.RotateLeft32(f, md5Shift[i/16][i%4])
+	}
+	state[0] = uint64(uint32(state[0]) + a)
+	state[1] = uint64(uint32(state[1]) + b)
+	state[2] = uint64(uint32(state[2]) + c)
+	state[3] = uint64(uint32(state[3]) + d)
+}
+
+//sha1Block is SHA-1's compression function
+func sha1Block(state []uint64, block []byte) {
+	//initialize message schedule
+	var w [80]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(block[4*i:])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+	}
+
+	a, b, c, d, e := uint32(state[0]), uint32(state[1]), uint32(state[2]), uint32(state[3]), uint32(state[4])
+	var f, k uint32
+	for i := 0; i < 80; i++ {
+		if i <= 19 {
+			f = (b & c) | ((^b) & d)
+			k = 0x5A827999
+		} else if i <= 39 {
+			f = b ^ c ^ d
+			k = 0x6ED9EBA1
+		} else if i <= 59 {
+			f = (b & c) | (b & d) | (c & d)
+			k = 0x8F1BBCDC
+		} else {
+			f = b ^ c ^ d
+			k = 0xCA62C1D6
+		}
+
+		tmp := bits.RotateLeft32(a, 5) + f + e + k + w[i]
+
+		e = d
+		d = c
+		c = bits.RotateLeft32(b, 30)
+		b = a
+		a = tmp
+	}
+
+	//add to result so far
+	state[0] = uint64(uint32(state[0]) + a)
+	state[1] = uint64(uint32(state[1]) + b)
+	state[2] = uint64(uint32(state[2]) + c)
+	state[3] = uint64(uint32(state[3]) + d)
+	state[4] = uint64(uint32(state[4]) + e)
+}
+
+//sha256Init is SHA-256's initial state, the first 32 bits of the
+//fractional parts of the square roots of the first 8 primes
+var sha256Init = []uint64{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+//sha256K are SHA-256's round constants, the first 32 bits of the
+//fractional parts of the cube roots of the first 64 primes
+var sha256K = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5,
+	0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3,
+	0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc,
+	0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7,
+	0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13,
+	0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3,
+	0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5,
+	0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208,
+	0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+//sha256Block is SHA-256's compression function
+func sha256Block(state []uint64, block []byte) {
+	var w [64]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(block[4*i:])
+	}
+	for i := 16; i < 64; i++ {
+		s0 := bits.RotateLeft32(w[i-15], -7) ^ bits.RotateLeft32(w[i-15], -18) ^ w[i-15]>>3
+		s1 := bits.RotateLeft32(w[i-2], -17) ^ bits.RotateLeft32(w[i-2], -19) ^ w[i-2]>>10
+		w[i] = w[i-16] + s0 + w[i-7] + s1
+	}
+
+	var v [8]uint32
+	for i := range v {
+		v[i] = uint32(state[i])
+	}
+	a, b, c, d, e, f, g, h := v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]
+	for i := 0; i < 64; i++ {
+		s1 := bits.RotateLeft32(e, -6) ^ bits.RotateLeft32(e, -11) ^ bits.RotateLeft32(e, -25)
+		ch := (e & f) ^ (^e & g)
+		t1 := h + s1 + ch + sha256K[i] + w[i]
+		s0 := bits.RotateLeft32(a, -2) ^ bits.RotateLeft32(a, -13) ^ bits.RotateLeft32(a, -22)
+		maj := (a & b) ^ (a & c) ^ (b & c)
+		t2 := s0 + maj
+		h, g, f, e, d, c, b, a = g, f, e, d+t1, c, b, a, t1+t2
+	}
+	for i, x := range []uint32{a, b, c, d, e, f, g, h} {
+		state[i] = uint64(v[i] + x)
+	}
+}
+
+//sha512Init is SHA-512's initial state, the first 64 bits of the
+//fractional parts of the square roots of the first 8 primes
+var sha512Init = []uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+//sha512K are SHA-512's round constants, the first 64 bits of the
+//fractional parts of the cube roots of the first 80 primes
+var sha512K = [80]uint64{
+	0x428a2f98d728ae22, 0x7137449123ef65cd, 0xb5c0fbcfec4d3b2f, 0xe9b5dba58189dbbc,
+	0x3956c25bf348b538, 0x59f111f1b605d019, 0x923f82a4af194f9b, 0xab1c5ed5da6d8118,
+	0xd807aa98a3030242, 0x12835b0145706fbe, 0x243185be4ee4b28c, 0x550c7dc3d5ffb4e2,
+	0x72be5d74f27b896f, 0x80deb1fe3b1696b1, 0x9bdc06a725c71235, 0xc19bf174cf692694,
+	0xe49b69c19ef14ad2, 0xefbe4786384f25e3, 0x0fc19dc68b8cd5b5, 0x240ca1cc77ac9c65,
+	0x2de92c6f592b0275, 0x4a7484aa6ea6e483, 0x5cb0a9dcbd41fbd4, 0x76f988da831153b5,
+	0x983e5152ee66dfab, 0xa831c66d2db43210, 0xb00327c898fb213f, 0xbf597fc7beef0ee4,
+	0xc6e00bf33da88fc2, 0xd5a79147930aa725, 0x06ca6351e003826f, 0x142929670a0e6e70,
+	0x27b70a8546d22ffc, 0x2e1b21385c26c926, 0x4d2c6dfc5ac42aed, 0x53380d139d95b3df,
+	0x650a73548baf63de, 0x766a0abb3c77b2a8, 0x81c2c92e47edaee6, 0x92722c851482353b,
+	0xa2bfe8a14cf10364, 0xa81a664bbc423001, 0xc24b8b70d0f89791, 0xc76c51a30654be30,
+	0xd192e819d6ef5218, 0xd69906245565a910, 0xf40e35855771202a, 0x106aa07032bbd1b8,
+	0x19a4c116b8d2d0c8, 0x1e376c085141ab53, 0x2748774cdf8eeb99, 0x34b0bcb5e19b48a8,
+	0x391c0cb3c5c95a63, 0x4ed8aa4ae3418acb, 0x5b9cca4f7763e373, 0x682e6ff3d6b2b8a3,
+	0x748f82ee5defb2fc, 0x78a5636f43172f60, 0x84c87814a1f0ab72, 0x8cc702081a6439ec,
+	0x90befffa23631e28, 0xa4506cebde82bde9, 0xbef9a3f7b2c67915, 0xc67178f2e372532b,
+	0xca273eceea26619c, 0xd186b8c721c0c207, 0xeada7dd6cde0eb1e, 0xf57d4f7fee6ed178,
+	0x06f067aa72176fba, 0x0a637dc5a2c898a6, 0x113f9804bef90dae, 0x1b710b35131c471b,
+	0x28db77f523047d84, 0x32caab7b40c72493, 0x3c9ebe0a15c9bebc, 0x431d67c49c100d4c,
+	0x4cc5d4becb3e42b6, 0x597f299cfc657e2a, 0x5fcb6fab3ad6faec, 0x6c44198c4a475817,
+}
+
+//sha512Block is SHA-512's compression function
+func sha512Block(state []uint64, block []byte) {
+	var w [80]uint64
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint64(block[8*i:])
+	}
+	for i := 16; i < 80; i++ {
+		s0 := bits.RotateLeft64(w[i-15], -1) ^ bits.RotateLeft64(w[i-15], -8) ^ w[i-15]>>7
+		s1 := bits.RotateLeft64(w[i-2], -19) ^ bits.RotateLeft64(w[i-2], -61) ^ w[i-2]>>6
+		w[i] = w[i-16] + s0 + w[i-7] + s1
+	}
+
+	a, b, c, d, e, f, g, h := state[0], state[1], state[2], state[3], state[4], state[5], state[6], state[7]
+	for i := 0; i < 80; i++ {
+		s1 := bits.RotateLeft64(e, -14) ^ bits.RotateLeft64(e, -18) ^ bits.RotateLeft64(e, -41)
+		ch := (e & f) ^ (^e & g)
+		t1 := h + s1 + ch + sha512K[i] + w[i]
+		s0 := bits.RotateLeft64(a, -28) ^ bits.RotateLeft64(a, -34) ^ bits.RotateLeft64(a, -39)
+		maj := (a & b) ^ (a & c) ^ (b & c)
+		t2 := s0 + maj
+		h, g, f, e, d, c, b, a = g, f, e, d+t1, c, b, a, t1+t2
+	}
+	for i, x := range []uint64{a, b, c, d, e, f, g, h} {
+		state[i] += x
+	}
+}
@@ -0,0 +1,169 @@
+package hashes
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+//MDDigest is a streaming hash.Hash computing an MDHash. It can start
+//from any chaining state, and can be saved and restored part way
+//through with MarshalBinary and UnmarshalBinary.
+type MDDigest struct {
+	h      *MDHash
+	state  []uint64
+	buf    []byte
+	length uint64
+	//start and startLen are what Reset goes back to
+	start    []uint64
+	startLen uint64
+}
+
+var _ hash.Hash = (*MDDigest)(nil)
+var _ encoding.BinaryMarshaler = (*MDDigest)(nil)
+var _ encoding.BinaryUnmarshaler = (*MDDigest)(nil)
+
+//New returns a digest for h from its initial state
+func (h *MDHash) New() *MDDigest {
+	return h.newDigest(h.Init, 0)
+}
+
+//newDigest returns a digest which has hashed length bytes, a whole
+//number of blocks, to state
+func (h *MDHash) newDigest(state []uint64, length uint64) *MDDigest {
+	d := &MDDigest{
+		h:        h,
+		buf:      make([]byte, 0, h.BlockSize),
+		start:    append([]uint64{}, state...),
+		startLen: length,
+	}
+	d.Reset()
+	return d
+}
+
+//NewFromState returns a digest which has already hashed length
+//bytes to the given chaining state, so that writing the rest of a
+//message to it gives the digest of the whole. Returns a non-nil error
+//if the state is the wrong size or length is not a whole number of
+//blocks.
+func (h *MDHash) NewFromState(state []uint64, length uint64) (*MDDigest, error) {
+	if len(state) != len(h.Init) {
+		return nil, fmt.Errorf("%v state must be %v words, got %v", h.Name, len(h.Init), len(state))
+	}
+	if length%uint64(h.BlockSize) != 0 {
+		return nil, fmt.Errorf("Length must be a whole number of %v-byte blocks", h.BlockSize)
+	}
+	return h.newDigest(state, length), nil
+}
+
+//NewFromDigest returns a digest which carries on from the digest of
+//some length-byte message, as if that message and its padding had
+//been written to it: anything written next is hashed after the glue
+//padding, as in length extension. Returns a non-nil error if the
+//digest is the wrong length.
+func (h *MDHash) NewFromDigest(digest []byte, length uint64) (*MDDigest, error) {
+	state, err := h.State(digest)
+	if err != nil {
+		return nil, err
+	}
+	return h.newDigest(state, length+uint64(len(h.Padding(length)))), nil
+}
+
+//Write adds more of the message to the digest. It never returns an
+//error.
+func (d *MDDigest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.length += uint64(n)
+	if len(d.buf) > 0 {
+		k := min(len(p), d.h.BlockSize-len(d.buf))
+		d.buf = append(d.buf, p[:k]...)
+		p = p[k:]
+		if len(d.buf) < d.h.BlockSize {
+			return n, nil
+		}
+		d.h.Compress(d.state, d.buf)
+		d.buf = d.buf[:0]
+	}
+	for len(p) >= d.h.BlockSize {
+		d.h.Compress(d.state, p[:d.h.BlockSize])
+		p = p[d.h.BlockSize:]
+	}
+	d.buf = append(d.buf, p...)
+	return n, nil
+}
+
+//Sum appends the digest of everything written so far to b, without
+//changing the digest's state
+func (d *MDDigest) Sum(b []byte) []byte {
+	state := append([]uint64{}, d.state...)
+	tail := append(append([]byte{}, d.buf...), d.h.Padding(d.length)...)
+	for i := 0; i < len(tail); i += d.h.BlockSize {
+		d.h.Compress(state, tail[i:i+d.h.BlockSize])
+	}
+	return append(b, d.h.Digest(state)...)
+}
+
+//Reset returns the digest to the state it was created with
+func (d *MDDigest) Reset() {
+	d.state = append(d.state[:0], d.start...)
+	d.buf = d.buf[:0]
+	d.length = d.startLen
+}
+
+//Size returns the digest length in bytes
+func (d *MDDigest) Size() int {
+	return d.h.Size()
+}
+
+//BlockSize returns the hash's block size in bytes
+func (d *MDDigest) BlockSize() int {
+	return d.h.BlockSize
+}
+
+//State returns the chaining state and the number of bytes hashed
+//into it, which leaves out any written bytes short of a whole block
+func (d *MDDigest) State() ([]uint64, uint64) {
+	return append([]uint64{}, d.state...), d.length - uint64(len(d.buf))
+}
+
+//marshalledSize returns the length of the encoding of a digest: the
+//hash's name and a colon, the state words, a block holding the
+//buffered bytes, and the length
+func (d *MDDigest) marshalledSize() int {
+	return len(d.h.Name) + 1 + 8*len(d.h.Init) + d.h.BlockSize + 8
+}
+
+//MarshalBinary encodes the digest's state, as the state words, the
+//buffered bytes and the length written, all big-endian
+func (d *MDDigest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, d.marshalledSize())
+	b = append(b, d.h.Name+":"...)
+	for _, w := range d.state {
+		b = binary.BigEndian.AppendUint64(b, w)
+	}
+	b = append(b, d.buf...)
+	b = append(b, make([]byte, d.h.BlockSize-len(d.buf))...)
+	return binary.BigEndian.AppendUint64(b, d.length), nil
+}
+
+//UnmarshalBinary restores a state encoded by MarshalBinary for the
+//same hash. Returns a non-nil error if it is for another hash or the
+//wrong length.
+func (d *MDDigest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(d.h.Name)+1 || string(b[:len(d.h.Name)+1]) != d.h.Name+":" {
+		return errors.New("Invalid hash state identifier")
+	}
+	if len(b) != d.marshalledSize() {
+		return errors.New("Invalid hash state size")
+	}
+	b = b[len(d.h.Name)+1:]
+	for i := range d.state {
+		d.state[i] = binary.BigEndian.Uint64(b[8*i:])
+	}
+	b = b[8*len(d.state):]
+	d.length = binary.BigEndian.Uint64(b[d.h.BlockSize:])
+	d.buf = append(d.buf[:0], b[:d.length%uint64(d.h.BlockSize)]...)
+	return nil
+}
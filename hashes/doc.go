@@ -0,0 +1,3 @@
+//Package hashes contains hash functions and MACs, including
+//the deliberately weak ones attacked in the challenges
+package hashes
@@ -0,0 +1,201 @@
+package hashes
+
+import (
+	"crypto/subtle"
+	"hash"
+	"time"
+)
+
+//MAC is a keyed hash construction under a fixed secret key
+type MAC interface {
+	//Sum returns the MAC of msg
+	Sum(msg []byte) []byte
+	//Size returns the MAC length in bytes
+	Size() int
+}
+
+//NewHash returns a new digest for h as a hash.Hash, so that h.NewHash
+//can be given to anything taking a hash constructor
+func (h *MDHash) NewHash() hash.Hash {
+	return h.New()
+}
+
+//HMAC is HMAC (RFC 2104) over any hash
+type HMAC struct {
+	New func() hash.Hash
+	Key []byte
+}
+
+//Sum returns the HMAC of msg
+func (m HMAC) Sum(msg []byte) []byte {
+	inner := m.New()
+	k := m.Key
+	if len(k) > inner.BlockSize() {
+		inner.Write(k)
+		k = inner.Sum(nil)
+		inner.Reset()
+	}
+	iKey := make([]byte, inner.BlockSize())
+	oKey := make([]byte, inner.BlockSize())
+	copy(iKey, k)
+	copy(oKey, k)
+	for i := range iKey {
+		iKey[i] ^= 0x36
+		oKey[i] ^= 0x5C
+	}
+
+	inner.Write(iKey)
+	inner.Write(msg)
+	outer := m.New()
+	outer.Write(oKey)
+	outer.Write(inner.Sum(nil))
+	return outer.Sum(nil)
+}
+
+//Size returns the MAC length in bytes
+func (m HMAC) Size() int {
+	return m.New().Size()
+}
+
+//PrefixMAC is the secret-prefix MAC H(key||msg), open to length
+//extension
+type PrefixMAC struct {
+	New func() hash.Hash
+	Key []byte
+}
+
+//Sum returns the MAC of msg
+func (m PrefixMAC) Sum(msg []byte) []byte {
+	return hashParts(m.New, m.Key, msg)
+}
+
+//Size returns the MAC length in bytes
+func (m PrefixMAC) Size() int {
+	return m.New().Size()
+}
+
+//SuffixMAC is the secret-suffix MAC H(msg||key), which falls to
+//collisions in H: colliding messages of the same length have the
+//same MAC
+type SuffixMAC struct {
+	New func() hash.Hash
+	Key []byte
+}
+
+//Sum returns the MAC of msg
+func (m SuffixMAC) Sum(msg []byte) []byte {
+	return hashParts(m.New, msg, m.Key)
+}
+
+//Size returns the MAC length in bytes
+func (m SuffixMAC) Size() int {
+	return m.New().Size()
+}
+
+//EnvelopeMAC is the envelope MAC H(key||msg||key)
+type EnvelopeMAC struct {
+	New func() hash.Hash
+	Key []byte
+}
+
+//Sum returns the MAC of msg
+func (m EnvelopeMAC) Sum(msg []byte) []byte {
+	return hashParts(m.New, m.Key, msg, m.Key)
+}
+
+//Size returns the MAC length in bytes
+func (m EnvelopeMAC) Size() int {
+	return m.New().Size()
+}
+
+//TruncatedMAC is the first Length bytes of another MAC. A Length of
+//zero or less, or more than the MAC's size, leaves it whole, so a
+//truncated MAC is never empty.
+type TruncatedMAC struct {
+	MAC    MAC
+	Length int
+}
+
+//Sum returns the MAC of msg
+func (m TruncatedMAC) Sum(msg []byte) []byte {
+	return m.MAC.Sum(msg)[:m.Size()]
+}
+
+//Size returns the MAC length in bytes
+func (m TruncatedMAC) Size() int {
+	if m.Length <= 0 {
+		return m.MAC.Size()
+	}
+	return min(m.Length, m.MAC.Size())
+}
+
+//hashParts returns the hash of the parts one after another
+func hashParts(newHash func() hash.Hash, parts ...[]byte) []byte {
+	h := newHash()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+//Verifier checks MACs of messages
+type Verifier interface {
+	Verify(msg, mac []byte) bool
+}
+
+//VerifierFunc allows an ordinary function to be used as a Verifier
+type VerifierFunc func(msg, mac []byte) bool
+
+//Verify calls f(msg, mac)
+func (f VerifierFunc) Verify(msg, mac []byte) bool {
+	return f(msg, mac)
+}
+
+//Comparison is how a MACVerifier compares MACs
+type Comparison int
+
+const (
+	//ConstantTime compares in time independent of the MACs
+	ConstantTime Comparison = iota
+	//EarlyExit compares a byte at a time, stopping at the first
+	//difference, as InsecureCompare in oracles/set_4.go
+	EarlyExit
+)
+
+//MACVerifier verifies MACs made by a MAC construction. With EarlyExit
+//comparison it sleeps for ByteDelay after each matching byte,
+//emphasising the timing leak. It rejects everything if the
+//construction's MACs are empty.
+type MACVerifier struct {
+	MAC       MAC
+	Compare   Comparison
+	ByteDelay time.Duration
+}
+
+//Verify reports whether mac is the MAC of msg
+func (v MACVerifier) Verify(msg, mac []byte) bool {
+	trueMac := v.MAC.Sum(msg)
+	if len(trueMac) == 0 {
+		return false
+	}
+	if v.Compare == EarlyExit {
+		return EarlyExitCompare(trueMac, mac, v.ByteDelay)
+	}
+	return subtle.ConstantTimeCompare(trueMac, mac) == 1
+}
+
+//EarlyExitCompare determines whether two byte slices contain the
+//same elements, stopping at the first difference and sleeping for
+//delay after each matching byte
+func EarlyExitCompare(b1, b2 []byte, delay time.Duration) bool {
+	if len(b1) != len(b2) {
+		return false
+	}
+	for i := range b1 {
+		if b1[i] != b2[i] {
+			return false
+		}
+		time.Sleep(delay)
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package hashes
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+//MDHash describes a Merkle-Damgard hash by its layout and compression
+//function: everything needed to hash from any chaining state, as
+//length extension does. New returns a streaming MDDigest for it.
+//Words are WordSize bytes, in big- or little-endian order, and so is
+//the message length in bits at the end of the padding, which takes
+//LengthSize bytes. The digest is the state words in order.
+type MDHash struct {
+	Name       string
+	BlockSize  int
+	WordSize   int
+	LengthSize int
+	BigEndian  bool
+	Init       []uint64
+	//Compress updates state with one block
+	Compress func(state []uint64, block []byte)
+}
+
+//MD4 is the MD4 hash
+var MD4 = &MDHash{
+	Name: "md4", BlockSize: 64, WordSize: 4, LengthSize: 8, BigEndian: false,
+	Init:     []uint64{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476},
+	Compress: md4Block,
+}
+
+//MD5 is the MD5 hash
+var MD5 = &MDHash{
+	Name: "md5", BlockSize: 64, WordSize: 4, LengthSize: 8, BigEndian: false,
+	Init:     []uint64{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476},
+	Compress: md5Block,
+}
+
+//SHA1 is the SHA-1 hash
+var SHA1 = &MDHash{
+	Name: "sha1", BlockSize: 64, WordSize: 4, LengthSize: 8, BigEndian: true,
+	Init:     []uint64{0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476, 0xC3D2E1F0},
+	Compress: sha1Block,
+}
+
+//SHA256 is the SHA-256 hash
+var SHA256 = &MDHash{
+	Name: "sha256", BlockSize: 64, WordSize: 4, LengthSize: 8, BigEndian: true,
+	Init:     sha256Init,
+	Compress: sha256Block,
+}
+
+//SHA512 is the SHA-512 hash
+var SHA512 = &MDHash{
+	Name: "sha512", BlockSize: 128, WordSize: 8, LengthSize: 16, BigEndian: true,
+	Init:     sha512Init,
+	Compress: sha512Block,
+}
+
+//MDHashes are the Merkle-Damgard hashes by name
+var MDHashes = map[string]*MDHash{
+	MD4.Name:    MD4,
+	MD5.Name:    MD5,
+	SHA1.Name:   SHA1,
+	SHA256.Name: SHA256,
+	SHA512.Name: SHA512,
+}
+
+//order returns the byte order of words and the length
+func (h *MDHash) order() binary.ByteOrder {
+	if h.BigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+//Size returns the digest length in bytes
+func (h *MDHash) Size() int {
+	return h.WordSize * len(h.Init)
+}
+
+//Padding returns the padding appended to a message of the given
+//length in bytes: 0x80, zeros up to the length field, and the
+//length in bits
+func (h *MDHash) Padding(length uint64) []byte {
+	padLen := h.BlockSize - int(length%uint64(h.BlockSize))
+	if padLen < 1+h.LengthSize {
+		padLen += h.BlockSize
+	}
+	pad := make([]byte, padLen)
+	pad[0] = 0x80
+	//lengths of 2^61 bytes and more don't fit in the low 64 bits
+	h.order().PutUint64(lengthField(pad[padLen-h.LengthSize:], h.BigEndian), length*8)
+	return pad
+}
+
+//lengthField returns the 8 bytes of a length field holding its low
+//64 bits
+func lengthField(field []byte, bigEndian bool) []byte {
+	if bigEndian {
+		return field[len(field)-8:]
+	}
+	return field[:8]
+}
+
+//Digest encodes a chaining state as a digest
+func (h *MDHash) Digest(state []uint64) []byte {
+	digest := make([]byte, h.Size())
+	for i, w := range state {
+		if h.WordSize == 4 {
+			h.order().PutUint32(digest[4*i:], uint32(w))
+		} else {
+			h.order().PutUint64(digest[8*i:], w)
+		}
+	}
+	return digest
+}
+
+//State decodes a digest as the chaining state it was made from.
+//Returns a non-nil error if the digest is the wrong length.
+func (h *MDHash) State(digest []byte) ([]uint64, error) {
+	if len(digest) != h.Size() {
+		return nil, fmt.Errorf("%v digest must be %v bytes, got %v", h.Name, h.Size(), len(digest))
+	}
+	state := make([]uint64, len(h.Init))
+	for i := range state {
+		if h.WordSize == 4 {
+			state[i] = uint64(h.order().Uint32(digest[4*i:]))
+		} else {
+			state[i] = h.order().Uint64(digest[8*i:])
+		}
+	}
+	return state, nil
+}
+
+//Sum returns the digest of msg
+func (h *MDHash) Sum(msg []byte) []byte {
+	d := h.New()
+	d.Write(msg)
+	return d.Sum(nil)
+}
@@ -0,0 +1,41 @@
+package hashes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"golang.org/x/crypto/twofish"
+)
+
+//BlockCipherMD implements a simplified MD iterated hash with a
+//digest size of 16 bits over any 16-byte block cipher: each block
+//of the message is encrypted under a key made from the current state,
+//and the last two bytes of the result become the new state.
+func BlockCipherMD(newCipher func(key []byte) (cipher.Block, error), M, H []byte) []byte {
+	key := bytesutil.PadLeft(H, 0x00, 16)
+	block := make([]byte, 16)
+	for i := 0; i*16 < len(M); i++ {
+		c, err := newCipher(key)
+		if err != nil {
+			panic(err)
+		}
+		c.Encrypt(block, M[i*16:(i+1)*16])
+		key = bytesutil.PadLeft(block[14:], 0x00, 16)
+	}
+	return key[14:]
+}
+
+//C52MD implements a simplified MD iterated hash using AES
+//with a digest size of 16 bits
+func C52MD(M, H []byte) []byte {
+	return BlockCipherMD(aes.NewCipher, M, H)
+}
+
+//C52TwofishMD implements a simplified MD iterated hash using
+//Twofish with a digest size of 16 bits
+func C52TwofishMD(M, H []byte) []byte {
+	return BlockCipherMD(func(key []byte) (cipher.Block, error) {
+		return twofish.NewCipher(key)
+	}, M, H)
+}
@@ -0,0 +1,281 @@
+//This file contains the cookie formats and the encoder and decoder
+
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Escaping is how a Format deals with metacharacters in keys and values
+type Escaping int
+
+//Escaping modes. EscapeNone passes metacharacters through, so input
+//can inject pairs. EscapeStrip deletes the separators, as ProfileFor
+//does. EscapePercent writes the separators, '%', '"', spaces and
+//non-printable bytes as %XX. EscapeQuote wraps keys and values which
+//contain metacharacters in double quotes, with '"' and '\' escaped
+//by a backslash.
+const (
+	EscapeNone Escaping = iota
+	EscapeStrip
+	EscapePercent
+	EscapeQuote
+)
+
+//DuplicatePolicy is what Decode does with a key which appears
+//more than once
+type DuplicatePolicy int
+
+//Duplicate key policies. DuplicateKeep keeps every pair, DuplicateFirst
+//and DuplicateLast keep only the first or last pair with each key, and
+//DuplicateReject makes Decode return an error.
+const (
+	DuplicateKeep DuplicatePolicy = iota
+	DuplicateFirst
+	DuplicateLast
+	DuplicateReject
+)
+
+//Format describes a cookie format. A strict format's Decode rejects
+//empty pairs, pairs without a key/value separator, empty keys,
+//unescaped separators in values, bad escapes and unterminated quotes;
+//otherwise Decode does the best it can with them.
+type Format struct {
+	PairSep    byte
+	KVSep      byte
+	Escaping   Escaping
+	Duplicates DuplicatePolicy
+	Strict     bool
+}
+
+//Query is the k=v&k=v format of challenge 13, escaping nothing
+var Query = Format{PairSep: '&', KVSep: '='}
+
+//Semicolon is the k=v;k=v format of challenges 16 and 26,
+//escaping nothing
+var Semicolon = Format{PairSep: ';', KVSep: '='}
+
+//With returns a copy of f using the given escaping
+func (f Format) With(e Escaping) Format {
+	f.Escaping = e
+	return f
+}
+
+//isMeta reports whether b must be escaped by f
+func (f Format) isMeta(b byte) bool {
+	return b == f.PairSep || b == f.KVSep
+}
+
+//percentEscaped reports whether EscapePercent writes b as %XX
+func (f Format) percentEscaped(b byte) bool {
+	return f.isMeta(b) || b == '%' || b == '"' || b <= ' ' || b >= 0x7f
+}
+
+//Escape escapes a key or value according to f
+func (f Format) Escape(s string) string {
+	var sb strings.Builder
+	switch f.Escaping {
+	case EscapeStrip:
+		for i := 0; i < len(s); i++ {
+			if !f.isMeta(s[i]) {
+				sb.WriteByte(s[i])
+			}
+		}
+	case EscapePercent:
+		for i := 0; i < len(s); i++ {
+			if f.percentEscaped(s[i]) {
+				fmt.Fprintf(&sb, "%%%02X", s[i])
+			} else {
+				sb.WriteByte(s[i])
+			}
+		}
+	case EscapeQuote:
+		if !strings.ContainsAny(s, string([]byte{f.PairSep, f.KVSep, '"', '\\'})) {
+			return s
+		}
+		sb.WriteByte('"')
+		for i := 0; i < len(s); i++ {
+			if s[i] == '"' || s[i] == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(s[i])
+		}
+		sb.WriteByte('"')
+	default:
+		return s
+	}
+	return sb.String()
+}
+
+//Encode writes c in format f, escaping each key and value
+func (f Format) Encode(c Cookie) string {
+	var sb strings.Builder
+	for i, p := range c {
+		if i > 0 {
+			sb.WriteByte(f.PairSep)
+		}
+		sb.WriteString(f.Escape(p.Key))
+		sb.WriteByte(f.KVSep)
+		sb.WriteString(f.Escape(p.Value))
+	}
+	return sb.String()
+}
+
+//unhex returns the value of a hex digit, or -1
+func unhex(b byte) int {
+	switch {
+	case b >= '0' && b <= '9':
+		return int(b - '0')
+	case b >= 'a' && b <= 'f':
+		return int(b-'a') + 10
+	case b >= 'A' && b <= 'F':
+		return int(b-'A') + 10
+	}
+	return -1
+}
+
+//unpercent decodes %XX escapes. Bad escapes are an error if
+//f is strict, and are left as they are otherwise.
+func (f Format) unpercent(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' {
+			if i+2 < len(s) && unhex(s[i+1]) >= 0 && unhex(s[i+2]) >= 0 {
+				sb.WriteByte(byte(unhex(s[i+1])<<4 | unhex(s[i+2])))
+				i += 2
+				continue
+			}
+			if f.Strict {
+				return "", fmt.Errorf("Bad escape at %q", s[i:])
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String(), nil
+}
+
+//scan reads a key (if key is true) or a value starting at s[pos],
+//returning it unescaped along with the position of the separator
+//or end of input which ends it
+func (f Format) scan(s string, pos int, key bool) (string, int, error) {
+	stop := func(b byte) bool {
+		return b == f.PairSep || (key && b == f.KVSep)
+	}
+	if f.Escaping == EscapeQuote && pos < len(s) && s[pos] == '"' {
+		var sb strings.Builder
+		for i := pos + 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				if i+1 < len(s) {
+					i++
+				}
+				sb.WriteByte(s[i])
+			case '"':
+				i++
+				if i < len(s) && !stop(s[i]) {
+					if f.Strict {
+						return "", 0, fmt.Errorf("Text after closing quote at %v", i)
+					}
+					rest, end, err := f.scan(s, i, key)
+					return sb.String() + rest, end, err
+				}
+				return sb.String(), i, nil
+			default:
+				sb.WriteByte(s[i])
+			}
+		}
+		if f.Strict {
+			return "", 0, errors.New("Unterminated quote")
+		}
+		return sb.String(), len(s), nil
+	}
+
+	end := pos
+	for end < len(s) && !stop(s[end]) {
+		if f.Strict && !key && s[end] == f.KVSep {
+			return "", 0, fmt.Errorf("Unescaped %q in value at %v", f.KVSep, end)
+		}
+		end++
+	}
+	tok := s[pos:end]
+	if f.Strict && f.Escaping == EscapeQuote && strings.ContainsAny(tok, `"\`) {
+		return "", 0, fmt.Errorf("Unquoted %q", tok)
+	}
+	if f.Escaping == EscapePercent {
+		var err error
+		if tok, err = f.unpercent(tok); err != nil {
+			return "", 0, err
+		}
+	}
+	return tok, end, nil
+}
+
+//Decode parses s in format f. Returns a non-nil error if f is strict
+//and s is malformed, or if a key repeats under DuplicateReject.
+func (f Format) Decode(s string) (Cookie, error) {
+	c := Cookie{}
+	pos := 0
+	for pos <= len(s) {
+		if pos == len(s) || s[pos] == f.PairSep {
+			//an empty pair
+			if f.Strict {
+				return nil, fmt.Errorf("Empty pair at %v", pos)
+			}
+			pos++
+			continue
+		}
+		k, end, err := f.scan(s, pos, true)
+		if err != nil {
+			return nil, err
+		}
+		v := ""
+		if end < len(s) && s[end] == f.KVSep {
+			v, end, err = f.scan(s, end+1, false)
+			if err != nil {
+				return nil, err
+			}
+		} else if f.Strict {
+			return nil, fmt.Errorf("No %q in pair at %v", f.KVSep, pos)
+		}
+		if f.Strict && k == "" {
+			return nil, fmt.Errorf("Empty key at %v", pos)
+		}
+		c = append(c, Pair{k, v})
+		pos = end + 1
+		if end == len(s) {
+			break
+		}
+	}
+	return f.applyDuplicates(c)
+}
+
+//applyDuplicates applies f's duplicate key policy to c
+func (f Format) applyDuplicates(c Cookie) (Cookie, error) {
+	count := make(map[string]int)
+	for _, p := range c {
+		count[p.Key]++
+	}
+	out := Cookie{}
+	seen := make(map[string]int)
+	for _, p := range c {
+		seen[p.Key]++
+		switch f.Duplicates {
+		case DuplicateFirst:
+			if seen[p.Key] > 1 {
+				continue
+			}
+		case DuplicateLast:
+			if seen[p.Key] < count[p.Key] {
+				continue
+			}
+		case DuplicateReject:
+			if count[p.Key] > 1 {
+				return nil, fmt.Errorf("Duplicate key %q", p.Key)
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
@@ -0,0 +1,97 @@
+//Package kv encodes and decodes key/value cookies, such as the
+//"email=foo@bar.com&uid=10&role=user" profiles of challenge 13 and
+//the "comment1=cooking%20MCs;userdata=..." strings of challenges 16
+//and 26. A Format gives the separators, how metacharacters in keys and
+//values are escaped, what to do with duplicate keys, and whether to
+//reject malformed input. Formats which escape nothing model the unsafe
+//encoders the challenges attack.
+package kv
+
+//Pair is a single key and value
+type Pair struct {
+	Key   string
+	Value string
+}
+
+//Cookie is an ordered list of key/value pairs. Keys may repeat,
+//depending on the Format it was decoded with.
+type Cookie []Pair
+
+//Get returns the value of the first pair with the given key,
+//and whether there is one
+func (c Cookie) Get(key string) (string, bool) {
+	for _, p := range c {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+//Values returns the values of every pair with the given key, in order
+func (c Cookie) Values(key string) []string {
+	values := []string{}
+	for _, p := range c {
+		if p.Key == key {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+//Keys returns the keys of c in order, without repeats
+func (c Cookie) Keys() []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, p := range c {
+		if !seen[p.Key] {
+			seen[p.Key] = true
+			keys = append(keys, p.Key)
+		}
+	}
+	return keys
+}
+
+//Map returns c as a map; where a key repeats, the last value wins
+func (c Cookie) Map() map[string]string {
+	m := make(map[string]string)
+	for _, p := range c {
+		m[p.Key] = p.Value
+	}
+	return m
+}
+
+//Set sets the value of the first pair with the given key, removing
+//any others, or adds a pair at the end if there is none
+func (c *Cookie) Set(key, value string) {
+	out := Cookie{}
+	found := false
+	for _, p := range *c {
+		if p.Key != key {
+			out = append(out, p)
+		} else if !found {
+			out = append(out, Pair{key, value})
+			found = true
+		}
+	}
+	if !found {
+		out = append(out, Pair{key, value})
+	}
+	*c = out
+}
+
+//Add adds a pair at the end, even if the key is already present
+func (c *Cookie) Add(key, value string) {
+	*c = append(*c, Pair{key, value})
+}
+
+//Del removes every pair with the given key
+func (c *Cookie) Del(key string) {
+	out := Cookie{}
+	for _, p := range *c {
+		if p.Key != key {
+			out = append(out, p)
+		}
+	}
+	*c = out
+}
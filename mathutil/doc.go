@@ -0,0 +1,4 @@
+//Package mathutil contains number-theoretic helpers, rational
+//intervals, int vectors, and the finite fields GF(2) and GF(2^128)
+//used by the attacks
+package mathutil
@@ -0,0 +1,308 @@
+//This file contains arithmetic in GF(2^128) as used by GCM,
+//and polynomials over it
+
+package mathutil
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"math/rand"
+)
+
+//GF128 is an element of GF(2^128) in GCM's bit order: reading the
+//16-byte string big-endian, the most significant bit is the
+//coefficient of x^0 and the least significant that of x^127. The
+//field is reduced by x^128 + x^7 + x^2 + x + 1.
+type GF128 struct {
+	Hi, Lo uint64
+}
+
+//GF128One is the multiplicative identity
+var GF128One = GF128{Hi: 1 << 63}
+
+//gf128R is x^128 reduced, shifted into place for Mul
+const gf128R = 0xe1 << 56
+
+//GF128FromBytes reads an element from a 16-byte string
+func GF128FromBytes(b []byte) GF128 {
+	return GF128{binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])}
+}
+
+//Bytes returns a as a 16-byte string
+func (a GF128) Bytes() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, a.Hi)
+	binary.BigEndian.PutUint64(b[8:], a.Lo)
+	return b
+}
+
+//IsZero reports whether a is zero
+func (a GF128) IsZero() bool {
+	return a.Hi == 0 && a.Lo == 0
+}
+
+//Add returns a + b, which is also a - b
+func (a GF128) Add(b GF128) GF128 {
+	return GF128{a.Hi ^ b.Hi, a.Lo ^ b.Lo}
+}
+
+//Mul returns a * b
+func (a GF128) Mul(b GF128) GF128 {
+	var z GF128
+	v := b
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = a.Hi >> (63 - i) & 1
+		} else {
+			bit = a.Lo >> (127 - i) & 1
+		}
+		if bit == 1 {
+			z = z.Add(v)
+		}
+		carry := v.Lo & 1
+		v.Lo = v.Lo>>1 | v.Hi<<63
+		v.Hi >>= 1
+		if carry == 1 {
+			v.Hi ^= gf128R
+		}
+	}
+	return z
+}
+
+//Square returns a * a
+func (a GF128) Square() GF128 {
+	return a.Mul(a)
+}
+
+//Inv returns the multiplicative inverse of a, a^(2^128 - 2), or
+//zero if a is zero
+func (a GF128) Inv() GF128 {
+	//2^128 - 2 = 2 + 4 + ... + 2^127
+	r := GF128One
+	s := a
+	for i := 1; i < 128; i++ {
+		s = s.Square()
+		r = r.Mul(s)
+	}
+	return r
+}
+
+//RandomGF128 returns a random element, using math/rand
+func RandomGF128() GF128 {
+	return GF128{rand.Uint64(), rand.Uint64()}
+}
+
+//GF128Poly is a polynomial over GF(2^128), with coefficients from
+//the constant term up. The zero polynomial may be empty.
+type GF128Poly []GF128
+
+//trim returns p without leading zero coefficients
+func (p GF128Poly) trim() GF128Poly {
+	n := len(p)
+	for n > 0 && p[n-1].IsZero() {
+		n--
+	}
+	return p[:n]
+}
+
+//Degree returns the degree of p, or -1 if p is zero
+func (p GF128Poly) Degree() int {
+	return len(p.trim()) - 1
+}
+
+//Add returns p + q
+func (p GF128Poly) Add(q GF128Poly) GF128Poly {
+	if len(q) > len(p) {
+		p, q = q, p
+	}
+	r := append(GF128Poly{}, p...)
+	for i, c := range q {
+		r[i] = r[i].Add(c)
+	}
+	return r.trim()
+}
+
+//Mul returns p * q
+func (p GF128Poly) Mul(q GF128Poly) GF128Poly {
+	p, q = p.trim(), q.trim()
+	if len(p) == 0 || len(q) == 0 {
+		return GF128Poly{}
+	}
+	r := make(GF128Poly, len(p)+len(q)-1)
+	for i, a := range p {
+		if a.IsZero() {
+			continue
+		}
+		for j, b := range q {
+			r[i+j] = r[i+j].Add(a.Mul(b))
+		}
+	}
+	return r.trim()
+}
+
+//DivMod returns the quotient and remainder of p divided by q,
+//panicking if q is zero
+func (p GF128Poly) DivMod(q GF128Poly) (GF128Poly, GF128Poly) {
+	q = q.trim()
+	if len(q) == 0 {
+		panic("Division by zero polynomial")
+	}
+	rem := append(GF128Poly{}, p.trim()...)
+	if len(rem) < len(q) {
+		return GF128Poly{}, rem
+	}
+	quo := make(GF128Poly, len(rem)-len(q)+1)
+	lead := q[len(q)-1].Inv()
+	for len(rem) >= len(q) {
+		shift := len(rem) - len(q)
+		c := rem[len(rem)-1].Mul(lead)
+		quo[shift] = c
+		for i, b := range q {
+			rem[shift+i] = rem[shift+i].Add(c.Mul(b))
+		}
+		rem = rem.trim()
+	}
+	return quo.trim(), rem
+}
+
+//Mod returns p mod q
+func (p GF128Poly) Mod(q GF128Poly) GF128Poly {
+	_, r := p.DivMod(q)
+	return r
+}
+
+//Monic returns p divided by its leading coefficient
+func (p GF128Poly) Monic() GF128Poly {
+	p = p.trim()
+	if len(p) == 0 {
+		return p
+	}
+	inv := p[len(p)-1].Inv()
+	r := make(GF128Poly, len(p))
+	for i, c := range p {
+		r[i] = c.Mul(inv)
+	}
+	return r
+}
+
+//Eval returns p(x)
+func (p GF128Poly) Eval(x GF128) GF128 {
+	var r GF128
+	for i := len(p) - 1; i >= 0; i-- {
+		r = r.Mul(x).Add(p[i])
+	}
+	return r
+}
+
+//GF128PolyGCD returns the monic greatest common divisor of p and q
+func GF128PolyGCD(p, q GF128Poly) GF128Poly {
+	p, q = p.trim(), q.trim()
+	for len(q) > 0 {
+		p, q = q, p.Mod(q)
+	}
+	return p.Monic()
+}
+
+//squareMod returns p^2 mod m
+func (p GF128Poly) squareMod(m GF128Poly) GF128Poly {
+	return p.Mul(p).Mod(m)
+}
+
+//Roots returns the distinct roots of p in GF(2^128), in no particular
+//order. The linear factors of p are found as its gcd with
+//X^(2^128) - X, and split apart with the trace map (Cantor-Zassenhaus).
+func (p GF128Poly) Roots() []GF128 {
+	f := p.Monic()
+	if f.Degree() <= 0 {
+		return nil
+	}
+	x := GF128Poly{{}, GF128One}
+	r := x.Mod(f)
+	for i := 0; i < 128; i++ {
+		r = r.squareMod(f)
+	}
+	g := GF128PolyGCD(f, r.Add(x))
+	return g.splitLinear()
+}
+
+//splitLinear returns the roots of p, a monic product of distinct
+//linear factors
+func (p GF128Poly) splitLinear() []GF128 {
+	switch p.Degree() {
+	case 0:
+		return nil
+	case 1:
+		//X + c has root c
+		return []GF128{p[0]}
+	}
+	for {
+		//Tr(aX) mod p is 0 or 1 at each root, so its gcd with p takes
+		//about half of the roots
+		ax := GF128Poly{{}, RandomGF128()}.Mod(p)
+		t := ax
+		for i := 1; i < 128; i++ {
+			ax = ax.squareMod(p)
+			t = t.Add(ax)
+		}
+		h := GF128PolyGCD(p, t)
+		if d := h.Degree(); d > 0 && d < p.Degree() {
+			q, _ := p.DivMod(h)
+			return append(h.splitLinear(), q.Monic().splitLinear()...)
+		}
+	}
+}
+
+//Coeff returns the coefficient of x^i in a, 0 or 1. Viewing a as a
+//vector over GF(2), this is its i-th entry.
+func (a GF128) Coeff(i int) uint64 {
+	if i < 64 {
+		return a.Hi >> (63 - i) & 1
+	}
+	return a.Lo >> (127 - i) & 1
+}
+
+//GF128Monomial returns x^i, the i-th unit vector, for i < 128
+func GF128Monomial(i int) GF128 {
+	if i < 64 {
+		return GF128{Hi: 1 << (63 - i)}
+	}
+	return GF128{Lo: 1 << (127 - i)}
+}
+
+//Dot returns the dot product of a and b as vectors over GF(2)
+func (a GF128) Dot(b GF128) uint64 {
+	return uint64(bits.OnesCount64(a.Hi&b.Hi)+bits.OnesCount64(a.Lo&b.Lo)) & 1
+}
+
+//GF128Table multiplies by a fixed element with precomputed tables,
+//one per byte of the other operand. It is much faster than Mul when
+//the same element is used many times, as in GHASH.
+type GF128Table [16][256]GF128
+
+//NewGF128Table returns the tables for multiplying by h
+func NewGF128Table(h GF128) *GF128Table {
+	t := new(GF128Table)
+	for i := 0; i < 16; i++ {
+		for k := 0; k < 8; k++ {
+			b := make([]byte, 16)
+			b[i] = 1 << k
+			t[i][1<<k] = GF128FromBytes(b).Mul(h)
+		}
+		for v := 1; v < 256; v++ {
+			low := v & -v
+			t[i][v] = t[i][v&(v-1)].Add(t[i][low])
+		}
+	}
+	return t
+}
+
+//Mul returns x times the table's element
+func (t *GF128Table) Mul(x GF128) GF128 {
+	var z GF128
+	for i := 0; i < 8; i++ {
+		z = z.Add(t[i][byte(x.Hi>>(56-8*i))])
+		z = z.Add(t[8+i][byte(x.Lo>>(56-8*i))])
+	}
+	return z
+}
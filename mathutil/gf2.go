@@ -0,0 +1,370 @@
+//This file contains vectors and polynomials over GF(2) packed into
+//words, Gaussian elimination, and Berlekamp-Massey
+
+package mathutil
+
+import "math/bits"
+
+//BitVector is a vector over GF(2); entry i is bit i%64 of word i/64
+type BitVector []uint64
+
+//NewBitVector returns the zero vector with n entries
+func NewBitVector(n int) BitVector {
+	return make(BitVector, (n+63)/64)
+}
+
+//Get returns entry i, 0 or 1
+func (v BitVector) Get(i int) uint64 {
+	return v[i/64] >> (i % 64) & 1
+}
+
+//Set sets entry i to 1
+func (v BitVector) Set(i int) {
+	v[i/64] |= 1 << (i % 64)
+}
+
+//Flip flips entry i
+func (v BitVector) Flip(i int) {
+	v[i/64] ^= 1 << (i % 64)
+}
+
+//Xor adds w into v in place. w must be no longer than v.
+func (v BitVector) Xor(w BitVector) {
+	for i, x := range w {
+		v[i] ^= x
+	}
+}
+
+//IsZero reports whether every entry of v is 0
+func (v BitVector) IsZero() bool {
+	for _, x := range v {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//Clone returns a copy of v
+func (v BitVector) Clone() BitVector {
+	return append(BitVector{}, v...)
+}
+
+//gf2Reduce puts copies of the rows, each of n entries, into reduced
+//row echelon form. Returns the nonzero rows and their pivot columns.
+func gf2Reduce(rows []BitVector, n int) ([]BitVector, []int) {
+	m := make([]BitVector, len(rows))
+	for i, r := range rows {
+		m[i] = r.Clone()
+	}
+	pivots := []int{}
+	rank := 0
+	for col := 0; col < n && rank < len(m); col++ {
+		p := -1
+		for i := rank; i < len(m); i++ {
+			if m[i].Get(col) == 1 {
+				p = i
+				break
+			}
+		}
+		if p < 0 {
+			continue
+		}
+		m[rank], m[p] = m[p], m[rank]
+		for i := range m {
+			if i != rank && m[i].Get(col) == 1 {
+				m[i].Xor(m[rank])
+			}
+		}
+		pivots = append(pivots, col)
+		rank++
+	}
+	return m[:rank], pivots
+}
+
+//GF2Rank returns the rank of a matrix over GF(2) given by its rows,
+//each of n entries
+func GF2Rank(rows []BitVector, n int) int {
+	_, pivots := gf2Reduce(rows, n)
+	return len(pivots)
+}
+
+//GF2Kernel returns a basis of the kernel of a matrix over GF(2)
+//given by its rows, each of n entries: the vectors x of n entries
+//with every row dotted with x zero. With no rows, this is every
+//unit vector.
+func GF2Kernel(rows []BitVector, n int) []BitVector {
+	m, pivots := gf2Reduce(rows, n)
+	isPivot := make([]bool, n)
+	for _, p := range pivots {
+		isPivot[p] = true
+	}
+	basis := []BitVector{}
+	for free := 0; free < n; free++ {
+		if isPivot[free] {
+			continue
+		}
+		x := NewBitVector(n)
+		x.Set(free)
+		for i, p := range pivots {
+			if m[i].Get(free) == 1 {
+				x.Set(p)
+			}
+		}
+		basis = append(basis, x)
+	}
+	return basis
+}
+
+//wordAt returns the 64 entries of v starting at entry off, with
+//entries past the end read as 0
+func (v BitVector) wordAt(off int) uint64 {
+	w, b := off/64, off%64
+	if w >= len(v) {
+		return 0
+	}
+	x := v[w] >> b
+	if b != 0 && w+1 < len(v) {
+		x |= v[w+1] << (64 - b)
+	}
+	return x
+}
+
+//GF2Poly is a polynomial over GF(2); the coefficient of x^i is
+//bit i%64 of word i/64. The zero polynomial may be empty.
+type GF2Poly []uint64
+
+//Degree returns the degree of p, or -1 if p is zero
+func (p GF2Poly) Degree() int {
+	for w := len(p) - 1; w >= 0; w-- {
+		if p[w] != 0 {
+			return 64*w + bits.Len64(p[w]) - 1
+		}
+	}
+	return -1
+}
+
+//Coeff returns the coefficient of x^i in p, 0 or 1
+func (p GF2Poly) Coeff(i int) uint64 {
+	if i/64 >= len(p) {
+		return 0
+	}
+	return p[i/64] >> (i % 64) & 1
+}
+
+//xorShifted adds q * x^shift into p in place. p must be long enough.
+func (p GF2Poly) xorShifted(q GF2Poly, shift int) {
+	w, b := shift/64, shift%64
+	for i, x := range q {
+		p[i+w] ^= x << b
+		if b != 0 && x>>(64-b) != 0 {
+			p[i+w+1] ^= x >> (64 - b)
+		}
+	}
+}
+
+//Mod returns p mod m, panicking if m is zero
+func (p GF2Poly) Mod(m GF2Poly) GF2Poly {
+	dm := m.Degree()
+	if dm < 0 {
+		panic("Division by zero polynomial")
+	}
+	r := append(GF2Poly{}, p...)
+	for i := r.Degree(); i >= dm; i-- {
+		if r.Coeff(i) == 1 {
+			r.xorShifted(m[:dm/64+1], i-dm)
+		}
+	}
+	return r[:min(len(r), dm/64+1)]
+}
+
+//SquareMod returns p^2 mod m. Squaring over GF(2) just spreads
+//the coefficients out, since the cross terms cancel.
+func (p GF2Poly) SquareMod(m GF2Poly) GF2Poly {
+	sq := make(GF2Poly, 2*len(p))
+	for i, x := range p {
+		for j := 0; j < 64; j++ {
+			if x>>j&1 == 1 {
+				k := 2 * (64*i + j)
+				sq[k/64] |= 1 << (k % 64)
+			}
+		}
+	}
+	return sq.Mod(m)
+}
+
+//MulXMod returns p * x mod m
+func (p GF2Poly) MulXMod(m GF2Poly) GF2Poly {
+	r := make(GF2Poly, len(p)+1)
+	r.xorShifted(p, 1)
+	return r.Mod(m)
+}
+
+//GF2PolyXPowMod returns x^n mod m
+func GF2PolyXPowMod(n uint64, m GF2Poly) GF2Poly {
+	r := GF2Poly{1}.Mod(m)
+	for i := bits.Len64(n) - 1; i >= 0; i-- {
+		r = r.SquareMod(m)
+		if n>>i&1 == 1 {
+			r = r.MulXMod(m)
+		}
+	}
+	return r
+}
+
+//GF2MinimalPolynomial returns the minimal polynomial of the first n
+//entries of s, a linear recurring sequence over GF(2), found with the
+//Berlekamp-Massey algorithm. The result p, of degree L, satisfies
+//p_0 s_k + p_1 s_(k+1) + ... + p_L s_(k+L) = 0 for every k. To be
+//sure of the right polynomial, n must be at least twice its degree.
+func GF2MinimalPolynomial(s BitVector, n int) GF2Poly {
+	//rev holds s backwards, so that the terms of each discrepancy
+	//are contiguous
+	rev := NewBitVector(n)
+	for i := 0; i < n; i++ {
+		if s.Get(i) == 1 {
+			rev.Set(n - 1 - i)
+		}
+	}
+	words := n/64 + 2
+	c, b := make(GF2Poly, words), make(GF2Poly, words)
+	c[0], b[0] = 1, 1
+	l, shift := 0, 1
+	for k := 0; k < n; k++ {
+		//d = sum of c_i s_(k-i) for i = 0 to l
+		var d uint64
+		off := n - 1 - k
+		for w := 0; w <= l/64; w++ {
+			d ^= c[w] & rev.wordAt(off+64*w)
+		}
+		if bits.OnesCount64(d)&1 == 0 {
+			shift++
+			continue
+		}
+		t := append(GF2Poly{}, c...)
+		c.xorShifted(b[:b.Degree()/64+1], shift)
+		if 2*l <= k {
+			l = k + 1 - l
+			b = t
+			shift = 1
+		} else {
+			shift++
+		}
+	}
+	//c is the connection polynomial; the minimal polynomial is its
+	//reverse
+	p := make(GF2Poly, l/64+1)
+	for i := 0; i <= l; i++ {
+		if c.Coeff(i) == 1 {
+			j := l - i
+			p[j/64] |= 1 << (j % 64)
+		}
+	}
+	return p
+}
+
+//GF2System is a system of linear equations over GF(2), kept in
+//echelon form as equations are added. Each stored equation has n+1
+//entries, the last being its right-hand side.
+type GF2System struct {
+	n      int
+	rows   []BitVector
+	pivots []int
+}
+
+//NewGF2System returns an empty system in n unknowns
+func NewGF2System(n int) *GF2System {
+	pivots := make([]int, n)
+	for i := range pivots {
+		pivots[i] = -1
+	}
+	return &GF2System{n: n, pivots: pivots}
+}
+
+//reduce reduces row by the stored equations in place, returning the
+//first unknown left in it, or n if none are
+func (s *GF2System) reduce(row BitVector) int {
+	for w := 0; w < len(row); {
+		x := row[w]
+		if w == s.n/64 {
+			//ignore the right-hand side
+			x &= 1<<(s.n%64) - 1
+		}
+		if x == 0 {
+			if 64*w >= s.n {
+				break
+			}
+			w++
+			continue
+		}
+		c := 64*w + bits.TrailingZeros64(x)
+		if c >= s.n {
+			break
+		}
+		p := s.pivots[c]
+		if p < 0 {
+			return c
+		}
+		for i := w; i < len(row); i++ {
+			row[i] ^= s.rows[p][i]
+		}
+	}
+	return s.n
+}
+
+//Add adds the equation row . x = rhs, where row has n entries.
+//Returns false, leaving the system unchanged, if the equation
+//contradicts those already added; one which follows from them is
+//dropped.
+func (s *GF2System) Add(row BitVector, rhs uint64) bool {
+	r := NewBitVector(s.n + 1)
+	copy(r, row)
+	if rhs&1 == 1 {
+		r.Set(s.n)
+	}
+	c := s.reduce(r)
+	if c == s.n {
+		return r.Get(s.n) == 0
+	}
+	s.pivots[c] = len(s.rows)
+	s.rows = append(s.rows, r)
+	return true
+}
+
+//Rank returns the number of independent equations added
+func (s *GF2System) Rank() int {
+	return len(s.rows)
+}
+
+//Eval returns v . x if the equations determine it, and false if not
+func (s *GF2System) Eval(v BitVector) (uint64, bool) {
+	r := NewBitVector(s.n + 1)
+	copy(r, v)
+	if s.reduce(r) != s.n {
+		return 0, false
+	}
+	return r.Get(s.n), true
+}
+
+//Solve returns a solution, with every unknown the equations leave
+//free set to 0
+func (s *GF2System) Solve() BitVector {
+	x := NewBitVector(s.n)
+	//each equation's other unknowns come after its pivot, so work
+	//back from the last unknown
+	for c := s.n - 1; c >= 0; c-- {
+		p := s.pivots[c]
+		if p < 0 {
+			continue
+		}
+		row := s.rows[p]
+		v := row.Get(s.n)
+		for w := c / 64; w < len(x); w++ {
+			v ^= uint64(bits.OnesCount64(row[w]&x[w])) & 1
+		}
+		if v == 1 {
+			x.Set(c)
+		}
+	}
+	return x
+}
@@ -1,4 +1,4 @@
-package main
+package mathutil
 
 import (
 	"math/big"
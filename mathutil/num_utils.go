@@ -1,7 +1,10 @@
 // This file contains assorted numeric utility functions
-package main
 
-import "math/big"
+package mathutil
+
+import (
+	"math/big"
+)
 
 //ModExp computes (a**x) mod m
 func ModExp(a, x, m *big.Int) (r *big.Int) {
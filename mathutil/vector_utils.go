@@ -1,6 +1,7 @@
 //This file contains utility functions for manipulating
 //int slices as vectors in Euclidean space
-package main
+
+package mathutil
 
 import (
 	"errors"
@@ -0,0 +1,5 @@
+//Package oracles contains the oracle interfaces the attacks
+//are written against, along with the toy oracles from the challenges.
+//Toy oracles hold their secrets themselves, so an attack given one
+//only sees what a real attacker would.
+package oracles
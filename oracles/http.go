@@ -0,0 +1,78 @@
+//This file contains oracles which query a remote server over HTTP.
+//The server should return a 200 OK response if the check passes
+//and a 500 error response if it fails.
+
+package oracles
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//HTTPPaddingOracle is a CBCPaddingOracle which queries a server
+//over HTTP. URL should be a printf-style format string with %x for
+//the IV followed by %x for the ciphertext.
+type HTTPPaddingOracle struct {
+	URL string
+}
+
+//ValidPadding asks the server whether ctext is properly padded under iv.
+//Panics if the request fails or the server returns any response other
+//than a 200 OK or a 500
+func (o HTTPPaddingOracle) ValidPadding(iv, ctext []byte) bool {
+	return httpCheck(fmt.Sprintf(o.URL, iv, ctext))
+}
+
+//HTTPRSAPaddingOracle is an RSAPaddingOracle which queries a server
+//over HTTP. URL should be a printf-style format string with %x for
+//the ciphertext.
+type HTTPRSAPaddingOracle struct {
+	URL string
+}
+
+//PKCS1Conformant asks the server whether ctext decrypts to a properly
+//padded plaintext. Panics if the request fails or the server returns
+//any response other than a 200 OK or a 500
+func (o HTTPRSAPaddingOracle) PKCS1Conformant(ctext []byte) bool {
+	return httpCheck(fmt.Sprintf(o.URL, ctext))
+}
+
+//HTTPTimingOracle is a TimingOracle which queries a server over
+//HTTP and measures the wall-clock time of each request. URL should be
+//a printf-style format string with %v for the (query-escaped) message
+//followed by %X for the MAC.
+type HTTPTimingOracle struct {
+	URL string
+}
+
+//TimedCheck asks the server whether mac is valid for msg. Panics if
+//the request fails or the server returns any response other than a
+//200 OK or a 500
+func (o HTTPTimingOracle) TimedCheck(msg, mac []byte) (bool, time.Duration) {
+	start := time.Now()
+	ok := httpCheck(fmt.Sprintf(o.URL, url.QueryEscape(string(msg)), mac))
+	return ok, time.Since(start)
+}
+
+//httpCheck issues a GET request to url and reports whether the
+//server returned 200 OK
+func httpCheck(url string) bool {
+	r, err := http.Get(url)
+	if err != nil {
+		panic(err)
+	}
+	//drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, r.Body)
+	r.Body.Close()
+	switch r.StatusCode {
+	case http.StatusOK:
+		return true
+	case http.StatusInternalServerError:
+		return false
+	}
+	panic("Unexpected Http status " + r.Status)
+}
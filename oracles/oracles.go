@@ -0,0 +1,204 @@
+//This file contains the oracle interfaces used by the attacks,
+//along with implementations wrapping the challenge oracles
+
+package oracles
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/alanese/cryptopals/hashes"
+)
+
+//CBCPaddingOracle reports whether a CBC ciphertext, decrypted
+//with the given IV, is properly PKCS#7-padded
+type CBCPaddingOracle interface {
+	ValidPadding(iv, ctext []byte) bool
+}
+
+//RSAPaddingOracle reports whether an RSA ciphertext decrypts
+//to a plaintext properly padded per PKCS#1v1.5
+type RSAPaddingOracle interface {
+	PKCS1Conformant(ctext []byte) bool
+}
+
+//RSAParityOracle reports whether an RSA ciphertext decrypts
+//to an odd plaintext
+type RSAParityOracle interface {
+	IsOdd(ctext *big.Int) bool
+}
+
+//ECBOracle encrypts attacker-controlled input (possibly along
+//with some secret data) under a fixed key
+type ECBOracle interface {
+	Encrypt(ptext []byte) []byte
+}
+
+//CompressionOracle returns the length of the compressed and
+//encrypted form of a request containing attacker-controlled input
+type CompressionOracle interface {
+	Length(ptext []byte) int
+}
+
+//TimingOracle checks a MAC for a message, reporting whether it was
+//accepted along with how long the check took
+type TimingOracle interface {
+	TimedCheck(msg, mac []byte) (ok bool, elapsed time.Duration)
+}
+
+//GCMOracle reports whether a GCM tag, possibly truncated, is right
+//for a ciphertext and additional data under a fixed key and nonce
+type GCMOracle interface {
+	ValidTag(aad, ctext, tag []byte) bool
+}
+
+//CTREditOracle replaces the plaintext at an offset of some stored
+//CTR ciphertext with newtext, re-encrypting it under the secret key
+type CTREditOracle interface {
+	Edit(offset int64, newtext []byte) error
+}
+
+//CBCPaddingOracleFunc allows an ordinary function to be
+//used as a CBCPaddingOracle
+type CBCPaddingOracleFunc func(iv, ctext []byte) bool
+
+//ValidPadding calls f(iv, ctext)
+func (f CBCPaddingOracleFunc) ValidPadding(iv, ctext []byte) bool {
+	return f(iv, ctext)
+}
+
+//RSAPaddingOracleFunc allows an ordinary function to be
+//used as an RSAPaddingOracle
+type RSAPaddingOracleFunc func(ctext []byte) bool
+
+//PKCS1Conformant calls f(ctext)
+func (f RSAPaddingOracleFunc) PKCS1Conformant(ctext []byte) bool {
+	return f(ctext)
+}
+
+//RSAParityOracleFunc allows an ordinary function to be
+//used as an RSAParityOracle
+type RSAParityOracleFunc func(ctext *big.Int) bool
+
+//IsOdd calls f(ctext)
+func (f RSAParityOracleFunc) IsOdd(ctext *big.Int) bool {
+	return f(ctext)
+}
+
+//ECBOracleFunc allows an ordinary function to be
+//used as an ECBOracle
+type ECBOracleFunc func(ptext []byte) []byte
+
+//Encrypt calls f(ptext)
+func (f ECBOracleFunc) Encrypt(ptext []byte) []byte {
+	return f(ptext)
+}
+
+//CompressionOracleFunc allows an ordinary function to be
+//used as a CompressionOracle
+type CompressionOracleFunc func(ptext []byte) int
+
+//Length calls f(ptext)
+func (f CompressionOracleFunc) Length(ptext []byte) int {
+	return f(ptext)
+}
+
+//TimingOracleFunc allows an ordinary function to be
+//used as a TimingOracle
+type TimingOracleFunc func(msg, mac []byte) (bool, time.Duration)
+
+//TimedCheck calls f(msg, mac)
+func (f TimingOracleFunc) TimedCheck(msg, mac []byte) (bool, time.Duration) {
+	return f(msg, mac)
+}
+
+//GCMOracleFunc allows an ordinary function to be
+//used as a GCMOracle
+type GCMOracleFunc func(aad, ctext, tag []byte) bool
+
+//ValidTag calls f(aad, ctext, tag)
+func (f GCMOracleFunc) ValidTag(aad, ctext, tag []byte) bool {
+	return f(aad, ctext, tag)
+}
+
+//CTREditOracleFunc allows an ordinary function to be
+//used as a CTREditOracle
+type CTREditOracleFunc func(offset int64, newtext []byte) error
+
+//Edit calls f(offset, newtext)
+func (f CTREditOracleFunc) Edit(offset int64, newtext []byte) error {
+	return f(offset, newtext)
+}
+
+//C12Oracle is the ECB oracle from challenge 12, wrapping
+//MysteryEncrypt with a secret key
+type C12Oracle struct {
+	Key []byte
+}
+
+//Encrypt encrypts ptext followed by the MYSTERY TEXT
+func (o C12Oracle) Encrypt(ptext []byte) []byte {
+	return MysteryEncrypt(ptext, o.Key)
+}
+
+//C14Oracle is the ECB oracle from challenge 14, wrapping
+//MysteryEncryptHard with a secret key and prefix
+type C14Oracle struct {
+	Prefix []byte
+	Key    []byte
+}
+
+//Encrypt encrypts the secret prefix, then ptext, then
+//the MYSTERY TEXT
+func (o C14Oracle) Encrypt(ptext []byte) []byte {
+	return MysteryEncryptHard(o.Prefix, ptext, o.Key)
+}
+
+//C17Oracle is the CBC padding oracle from challenge 17,
+//wrapping Challenge17Decrypt with a secret key
+type C17Oracle struct {
+	Key []byte
+}
+
+//ValidPadding decrypts ctext with the secret key and
+//checks its padding
+func (o C17Oracle) ValidPadding(iv, ctext []byte) bool {
+	return Challenge17Decrypt(ctext, o.Key, iv) == nil
+}
+
+//C46Oracle is the RSA parity oracle from challenge 46,
+//wrapping C46RSAParityOracle with the private key [D, N]
+type C46Oracle struct {
+	D *big.Int
+	N *big.Int
+}
+
+//IsOdd decrypts ctext and checks the parity of the plaintext
+func (o C46Oracle) IsOdd(ctext *big.Int) bool {
+	return C46RSAParityOracle(ctext, o.D, o.N)
+}
+
+//C47Oracle is the RSA padding oracle from challenges 47 and 48,
+//wrapping C47PaddingOracle with the private key [D, N]
+type C47Oracle struct {
+	D *big.Int
+	N *big.Int
+}
+
+//PKCS1Conformant decrypts ctext and checks its padding
+func (o C47Oracle) PKCS1Conformant(ctext []byte) bool {
+	return C47PaddingOracle(ctext, o.D, o.N)
+}
+
+//C64Oracle is the GCM oracle from challenge 64, wrapping
+//VerifyAESGCM with a secret key and a fixed nonce
+type C64Oracle struct {
+	Key   []byte
+	Nonce []byte
+}
+
+//ValidTag checks the truncated tag for the ciphertext and additional
+//data under the secret key and nonce
+func (o C64Oracle) ValidTag(aad, ctext, tag []byte) bool {
+	return hashes.VerifyAESGCM(tag, aad, ctext, o.Nonce, o.Key)
+}
@@ -0,0 +1,125 @@
+package oracles
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/kv"
+)
+
+//MysteryEncrypt sticks given plaintext on the front
+//of some MYSTERY TEXT, pads it with PKCS#7,
+//and encrypts with AES-ECB
+func MysteryEncrypt(ptext []byte, key []byte) []byte {
+	mysteryPtext := "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK"
+	mysteryBytes, _ := base64.StdEncoding.DecodeString(mysteryPtext)
+	newPtext := append(ptext, mysteryBytes...)
+	newPtext = ciphers.PKCSPad(newPtext, 16)
+	return ciphers.EncryptAESECB(newPtext, key)
+
+}
+
+//MysteryEncryptHard wraps MysteryEncrypt to add padding
+//to the front of the plaintext before encryption
+func MysteryEncryptHard(initialPad, ptext, key []byte) []byte {
+	return MysteryEncrypt(append(initialPad, ptext...), key)
+}
+
+//ProfileCodec is the format of the profiles made by ProfileFor:
+//k=v&k=v, with & and = stripped from values and the last of
+//any repeated key winning
+var ProfileCodec = kv.Format{
+	PairSep:    '&',
+	KVSep:      '=',
+	Escaping:   kv.EscapeStrip,
+	Duplicates: kv.DuplicateLast,
+	Strict:     true,
+}
+
+//ParseKv parses something of the form k1=v1&k2=v2&k3=v3
+//into a string-string map. Returns a non-nil error on a
+//malformed input, including empty keys or values
+func ParseKv(s string) (map[string]string, error) {
+	c, err := ProfileCodec.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range c {
+		if p.Value == "" {
+			return nil, errors.New("Malformed string")
+		}
+	}
+	return c.Map(), nil
+}
+
+//ProfileEscape strips the encoding characters & and =
+//from an email address for ProfileFor
+func ProfileEscape(email string) string {
+	return ProfileCodec.Escape(email)
+}
+
+//ProfileFor constructs a profile as per cryptopals challenge 13
+func ProfileFor(email string) string {
+	return ProfileForCodec(email, ProfileCodec)
+}
+
+//ProfileForCodec constructs a profile as per challenge 13 in the
+//given format, so profiles can be made by safe or unsafe encoders.
+//With kv.Query, which escapes nothing, an email address can add
+//its own role.
+func ProfileForCodec(email string, codec kv.Format) string {
+	uid := fmt.Sprintf("%X", rand.Int())
+	return codec.Encode(kv.Cookie{
+		{Key: "email", Value: email},
+		{Key: "uid", Value: uid},
+		{Key: "role", Value: "user"},
+	})
+}
+
+//EncryptProfile encrypts the given profile string using AES-ECB
+func EncryptProfile(profile string, key []byte) []byte {
+	pText := ciphers.PKCSPad([]byte(profile), 16)
+	return ciphers.EncryptAESECB(pText, key)
+}
+
+//DecryptParseProfile decrypts and parses a profile encrypted
+//with AES-ECB
+func DecryptParseProfile(ctext, key []byte) (map[string]string, error) {
+	pText := string(ciphers.DecryptAESECB(ctext, key))
+	return ParseKv(pText)
+}
+
+//C16Codec is the format of the strings made by Challenge16Func
+//and Challenge26Func: k=v;k=v with values percent-encoded
+var C16Codec = kv.Semicolon.With(kv.EscapePercent)
+
+//C16Cookie builds the string Challenge16Func and Challenge26Func
+//encrypt around userdata in the given format, so the strings can be
+//made by safe or unsafe encoders. With C16Codec it is
+//"comment1=cooking%20MCs;userdata=...;comment2=%20like%20a%20pound%20of%20bacon".
+func C16Cookie(userdata string, codec kv.Format) []byte {
+	return []byte(codec.Encode(kv.Cookie{
+		{Key: "comment1", Value: "cooking MCs"},
+		{Key: "userdata", Value: userdata},
+		{Key: "comment2", Value: " like a pound of bacon"},
+	}))
+}
+
+//Challenge16Func generates, pads, and encrypts a
+//data string as per challenge 16
+func Challenge16Func(userdata string, secretKey, iv []byte) []byte {
+	ptext := ciphers.PKCSPad(C16Cookie(userdata, C16Codec), 16)
+	return ciphers.EncryptAESCBC(ptext, secretKey, iv)
+}
+
+//Challenge16AdminCheck decrypts a byte slice
+//and checks whether it contains the text ";admin=true;"
+func Challenge16AdminCheck(data []byte, secretkey, iv []byte) bool {
+	ptext := ciphers.DecryptAESCBC(data, secretkey, iv)
+	ptext, _ = ciphers.StripPKCS7Padding(ptext, 16)
+	return bytes.Contains(ptext, []byte(";admin=true;"))
+}
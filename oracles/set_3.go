@@ -0,0 +1,74 @@
+package oracles
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/rand"
+	"time"
+
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/prng"
+)
+
+//Challenge17Encrypt randomly chooses one of 10 Base64-encoded
+//strings, decodes it, pads it, and encrypts it with AES-CBC
+//using the given key and IV
+func Challenge17Encrypt(key, iv []byte) []byte {
+	options := []string{"MDAwMDAwTm93IHRoYXQgdGhlIHBhcnR5IGlzIGp1bXBpbmc=",
+		"MDAwMDAxV2l0aCB0aGUgYmFzcyBraWNrZWQgaW4gYW5kIHRoZSBWZWdhJ3MgYXJlIHB1bXBpbic=",
+		"MDAwMDAyUXVpY2sgdG8gdGhlIHBvaW50LCB0byB0aGUgcG9pbnQsIG5vIGZha2luZw==",
+		"MDAwMDAzQ29va2luZyBNQydzIGxpa2UgYSBwb3VuZCBvZiBiYWNvbg==",
+		"MDAwMDA0QnVybmluZyAnZW0sIGlmIHlvdSBhaW4ndCBxdWljayBhbmQgbmltYmxl",
+		"MDAwMDA1SSBnbyBjcmF6eSB3aGVuIEkgaGVhciBhIGN5bWJhbA==",
+		"MDAwMDA2QW5kIGEgaGlnaCBoYXQgd2l0aCBhIHNvdXBlZCB1cCB0ZW1wbw==",
+		"MDAwMDA3SSdtIG9uIGEgcm9sbCwgaXQncyB0aW1lIHRvIGdvIHNvbG8=",
+		"MDAwMDA4b2xsaW4nIGluIG15IGZpdmUgcG9pbnQgb2g=",
+		"MDAwMDA5aXRoIG15IHJhZy10b3AgZG93biBzbyBteSBoYWlyIGNhbiBibG93"}
+	pText, _ := base64.StdEncoding.DecodeString(options[rand.Intn(len(options))])
+	pText = ciphers.PKCSPad(pText, 16)
+	return ciphers.EncryptAESCBC(pText, key, iv)
+}
+
+//Challenge17Decrypt decrypts the given AES-CBC ciphertext
+//with the given key and IV and returns a nil error if
+//the plaintext is properly PKCS#7-padded, non-nil otherwise
+func Challenge17Decrypt(ctext, key, iv []byte) error {
+	ptext := ciphers.DecryptAESCBC(ctext, key, iv)
+	_, err := ciphers.StripPKCS7Padding(ptext, 16)
+	return err
+}
+
+//Challenge22RandomNum creates a new Twister seeded with the current time
+//plus a random offset of 40 to 940 seconds. Returns the first random value
+//from the twister, and the random seed (only used so I can see if I did
+//the challenge correctly)
+func Challenge22RandomNum() (uint32, uint32) {
+	timeSeed := uint32(time.Now().Unix()) + uint32(rand.Intn(900)+40)
+	t := prng.NewTwister(uint32(timeSeed))
+	return t.Next(), timeSeed
+}
+
+//C24GenerateResetToken generates and encrypts a "reset token"
+//using the given username. Encrypts using the MT19937 stream
+//cipher seeded with the current unix timestamp
+func C24GenerateResetToken(uname string) []byte {
+	head := "reset_password?uname=" + uname
+	tokenBytes := []byte(head)
+	seed := uint32(time.Now().Unix())
+
+	return ciphers.EncryptMT19937Stream(tokenBytes, seed)
+}
+
+//C24ValidateToken checks whether the given bytes are a valid
+//"reset token" as created by C24GenerateResetToken and encrypted
+//with the current Unix timestamp
+func C24ValidateToken(token []byte) bool {
+	if len(token) < 21 {
+		return false
+	}
+	seed := uint32(time.Now().Unix())
+	decryptedToken := ciphers.EncryptMT19937Stream(token, seed)
+	testHead := []byte("reset_password?uname=")
+	return bytes.Equal(testHead, decryptedToken[:21])
+
+}
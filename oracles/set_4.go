@@ -0,0 +1,106 @@
+package oracles
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/hashes"
+)
+
+//C25Edit decrypts the ciphertext (using AES-CTR with the given key),
+//truncates after offset bytes, adds newText, then re-encrypts.
+func C25Edit(ctext, key, newtext []byte, offset int) []byte {
+	nonce := make([]byte, 8)
+	originalPtext := ciphers.EncryptAESCTR(ctext, key, nonce)
+	newPtext := bytes.NewBuffer([]byte{})
+	newPtext.Write(originalPtext[:offset])
+	newPtext.Write(newtext)
+	newCtext := ciphers.EncryptAESCTR(newPtext.Bytes(), key, nonce)
+	return newCtext
+}
+
+//C25Editor is the edit oracle from challenge 25 over ciphertext in
+//random-access storage, such as a file or a bytesutil.MemFile. Unlike
+//C25Edit, it replaces the plaintext in place, re-encrypting only the
+//bytes edited, so it is usable on large ciphertexts.
+type C25Editor struct {
+	Storage io.WriterAt
+	Key     []byte
+}
+
+//Edit replaces the plaintext at offset with newtext, encrypting with
+//AES-CTR under the secret key, a zero nonce and LittleEndianCounter
+//as C25Edit does
+func (e C25Editor) Edit(offset int64, newtext []byte) error {
+	b, err := aes.NewCipher(e.Key)
+	if err != nil {
+		return err
+	}
+	ctr := ciphers.NewSeekableCTR(b, make([]byte, 8), ciphers.LittleEndianCounter)
+	_, err = ciphers.NewCTRFile(ctr, nil, e.Storage).WriteAt(newtext, offset)
+	return err
+}
+
+//Challenge26Func generates, pads, and encrypts a
+//data string as per challenge 26 (C16 reimplemented with CTR)
+func Challenge26Func(userdata string, secretKey []byte) []byte {
+	ptext := ciphers.PKCSPad(C16Cookie(userdata, C16Codec), 16)
+	nonce := make([]byte, 8) //use 0s as the nonce
+	return ciphers.EncryptAESCTR(ptext, secretKey, nonce)
+}
+
+//Challenge26AdminCheck decrypts a byte slice
+//and checks whether it contains the text ";admin=true;"
+//(C16 reimplemented with CTR)
+func Challenge26AdminCheck(data []byte, secretkey []byte) bool {
+	nonce := make([]byte, 8) //use 0s for nonce
+	ptext := ciphers.EncryptAESCTR(data, secretkey, nonce)
+	ptext, _ = ciphers.StripPKCS7Padding(ptext, 16)
+	return bytes.Contains(ptext, []byte(";admin=true;"))
+}
+
+//Challenge27VerifyDecrypt attempts to decrypt the ciphertext using the key
+//as the IV; it returns the decrypted plaintext and a non-nil error if the
+//plaintext contains any non-ASCII bytes, and returns two nils otherwise
+func Challenge27VerifyDecrypt(ctext, key []byte) ([]byte, error) {
+	pText := ciphers.DecryptAESCBC(ctext, key, key)
+	for _, v := range pText {
+		if v > 127 {
+			return pText, errors.New("Invalid character")
+		}
+	}
+	return nil, nil
+}
+
+//C29ValidateMAC tests whether the given digest is the SHA-1
+//hash of key || message. An attacker exploiting this function
+//doesn't actually know key; it's passed as a parameter so I don't
+//have to maintain global variables.
+func C29ValidateMAC(key, message, digest []byte) bool {
+	v := hashes.MACVerifier{MAC: hashes.PrefixMAC{New: hashes.SHA1.NewHash, Key: key}}
+	return v.Verify(message, digest)
+}
+
+//C30ValidateMAC checks if the digest is the MD4 hash
+//of key || message
+func C30ValidateMAC(key, message, digest []byte) bool {
+	v := hashes.MACVerifier{MAC: hashes.PrefixMAC{New: hashes.MD4.NewHash, Key: key}}
+	return v.Verify(message, digest)
+}
+
+//InsecureCompare determines whether two byte slices
+//contain the same elements with early exit, with an
+//artificially-emphasized timing leak
+func InsecureCompare(b1, b2 []byte) bool {
+	return InsecureCompareDelay(b1, b2, 50*time.Millisecond)
+}
+
+//InsecureCompareDelay is InsecureCompare with a chosen
+//delay per matching byte
+func InsecureCompareDelay(b1, b2 []byte, delay time.Duration) bool {
+	return hashes.EarlyExitCompare(b1, b2, delay)
+}
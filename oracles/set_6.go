@@ -0,0 +1,85 @@
+package oracles
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/pubkey"
+)
+
+//C47PaddingOracle decrypts m with the RSA private keypair [d, n]
+//and checks whether the plaintext is properly paddid according to
+//PKCS#1v1.5
+func C47PaddingOracle(m []byte, d, n *big.Int) bool {
+	pText := pubkey.RSADecryptPad(m, d, n)
+	return ciphers.RSAPKCS1Validate(pText)
+}
+
+//C42CheckHash determines whether the digest in digestInfo is the SHA-256 digest
+//produced by msg
+func C42CheckHash(digestinfo pubkey.RSASignatureDigestInfo, msg []byte) bool {
+	verifyHash := digestinfo.Digest
+	targetHash := sha256.Sum256(msg)
+	return bytes.Equal(verifyHash, targetHash[:])
+}
+
+//C42CheckRSASignature determines (incorrectly) if rsaSignature is a properly
+//padded and encrypted RSA SHA-256 signature for msg; the function does not
+//properly ensure the padding is long enough, enabling Bleichenbacher's attack
+//for sufficiently long n
+func C42CheckRSASignature(msg []byte, rsaSignature []byte, e, n *big.Int) bool {
+	sig := pubkey.RSAEncryptPad(rsaSignature, e, n)
+
+	notPadding := 0
+	padding00 := 1
+	padding0001 := 2
+	paddingFF := 3
+	state := 0
+	i := 0
+	//I feel like there's a better way to do this than essentially implementing a DFA
+	for i < len(sig) {
+		switch state {
+		case notPadding:
+			if sig[i] == 0x00 {
+				state = padding00
+			}
+		case padding00:
+			if sig[i] == 0x01 {
+				state = padding0001
+			} else {
+				state = notPadding
+			}
+		case padding0001:
+			if sig[i] == 0x00 {
+				digestInfo := pubkey.UnmarshalDigestInfo(sig[i+1:])
+				return C42CheckHash(digestInfo, msg) //deliberately fail to check right-justification
+			} else if sig[i] == 0xFF {
+				state = paddingFF
+			} else {
+				state = notPadding
+			}
+		case paddingFF:
+			if sig[i] == 0x00 {
+				digestInfo := pubkey.UnmarshalDigestInfo(sig[i+1:])
+				return C42CheckHash(digestInfo, msg) //deliberately fail to check right-justification
+			} else if sig[i] != 0xFF {
+				state = notPadding
+			}
+		default:
+			panic("Unexpected state error") //this shouldn't happen
+
+		}
+		i++
+	}
+	return false
+
+}
+
+//C46RSAParityOracle decrypts ciphertext with the RSA private
+//key [d, n] and returns true iff the resulting plaintext is odd
+func C46RSAParityOracle(ciphertext, d, n *big.Int) bool {
+	plaintext := big.NewInt(0).Exp(ciphertext, d, n)
+	return plaintext.Bit(0) != 0
+}
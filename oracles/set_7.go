@@ -0,0 +1,54 @@
+package oracles
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"strconv"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+)
+
+//C51FormatRequest formats a request per challenge 51
+func C51FormatRequest(p []byte) []byte {
+	return []byte("POST / HTTP/1.1\n" +
+		"Host: hapless.com\n" +
+		"Cookie: sessionid=TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=\n" +
+		"Content-length: " + strconv.Itoa(len(p)) + "\n" +
+		string(p))
+}
+
+//C51OracleStream implements the compression oracle in challenge 51
+//Encrypts with a MT19937 stream cipher because it's a stream
+//cipher i have implemented
+func C51OracleStream(p []byte) int {
+	key := rand.Uint32()
+	rq := C51FormatRequest(p)
+	compressed := bytesutil.CompressDEFLATE(rq)
+	encrypted := ciphers.EncryptMT19937Stream(compressed, key)
+	return len(encrypted)
+}
+
+//C51OracleCBC implements a CBC version of the compression
+//oracle in challenge 51
+func C51OracleCBC(p []byte) int {
+	key := bytesutil.GenerateRandomByteSlice(16)
+	iv := bytesutil.GenerateRandomByteSlice(16)
+	rq := C51FormatRequest(p)
+	compressed := bytesutil.CompressDEFLATE(rq)
+	padded := ciphers.PKCSPad(compressed, 16)
+	encrypted := ciphers.EncryptAESCBC(padded, key, iv)
+	return len(encrypted)
+}
+
+//C56Secret is the b64-encoded secret cookie for challenge 56
+const C56Secret = "QkUgU1VSRSBUTyBEUklOSyBZT1VSIE9WQUxUSU5F"
+
+//C56Oracle appends a secret cookie to the end of the given
+//request and encrypts it with RC4 with a random 128-bit key
+func C56Oracle(r []byte) []byte {
+	cookie, _ := base64.StdEncoding.DecodeString(C56Secret)
+	ptext := append(r, cookie...)
+	key := bytesutil.GenerateRandomByteSlice(16)
+	return ciphers.EncryptRC4(ptext, key)
+}
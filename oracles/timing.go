@@ -0,0 +1,91 @@
+//This file contains timing oracles for the HMAC timing leak from
+//challenges 31 and 32, simulated or timing any Verifier
+
+package oracles
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/alanese/cryptopals/hashes"
+)
+
+//SimulatedTimingOracle is a TimingOracle which checks HMAC-SHA1
+//signatures, or those of MAC if it is set, in-process with a
+//simulated clock rather than sleeping.
+//A check appears to take Overhead, plus ByteDelay for each leading
+//byte of the MAC which is correct, plus Gaussian noise with standard
+//deviation Jitter. The noise is seeded with Seed when the first
+//check is made, so the same sequence of checks always gives the same
+//timings, and an oracle can be made with a struct literal.
+type SimulatedTimingOracle struct {
+	Key       []byte
+	MAC       hashes.MAC
+	ByteDelay time.Duration
+	Overhead  time.Duration
+	Jitter    time.Duration
+	Seed      int64
+	rnd       *rand.Rand
+}
+
+//NewSimulatedTimingOracle creates a simulated timing oracle with
+//the given secret key and timing parameters, seeding its noise
+//source with seed
+func NewSimulatedTimingOracle(key []byte, byteDelay, overhead, jitter time.Duration, seed int64) *SimulatedTimingOracle {
+	return &SimulatedTimingOracle{
+		Key:       key,
+		ByteDelay: byteDelay,
+		Overhead:  overhead,
+		Jitter:    jitter,
+		Seed:      seed,
+	}
+}
+
+//NewSimulatedMACTimingOracle creates a simulated timing oracle for
+//any MAC construction, as NewSimulatedTimingOracle
+func NewSimulatedMACTimingOracle(mac hashes.MAC, byteDelay, overhead, jitter time.Duration, seed int64) *SimulatedTimingOracle {
+	o := NewSimulatedTimingOracle(nil, byteDelay, overhead, jitter, seed)
+	o.MAC = mac
+	return o
+}
+
+//TimedCheck checks mac against the MAC of msg, comparing with
+//early exit, and returns the simulated time taken
+func (o *SimulatedTimingOracle) TimedCheck(msg, mac []byte) (bool, time.Duration) {
+	var trueMac []byte
+	if o.MAC != nil {
+		trueMac = o.MAC.Sum(msg)
+	} else {
+		trueMac = hashes.HMACSHA1(o.Key, msg)
+	}
+	matched := 0
+	if len(mac) == len(trueMac) {
+		for matched < len(mac) && mac[matched] == trueMac[matched] {
+			matched++
+		}
+	}
+
+	if o.rnd == nil {
+		o.rnd = rand.New(rand.NewSource(o.Seed))
+	}
+	elapsed := o.Overhead + time.Duration(matched)*o.ByteDelay
+	elapsed += time.Duration(o.rnd.NormFloat64() * float64(o.Jitter))
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return matched == len(trueMac), elapsed
+}
+
+//VerifierTimingOracle is a TimingOracle which times a Verifier on
+//the wall clock, such as a hashes.MACVerifier comparing with early
+//exit
+type VerifierTimingOracle struct {
+	Verifier hashes.Verifier
+}
+
+//TimedCheck verifies mac for msg and returns the time it took
+func (o VerifierTimingOracle) TimedCheck(msg, mac []byte) (bool, time.Duration) {
+	start := time.Now()
+	ok := o.Verifier.Verify(msg, mac)
+	return ok, time.Since(start)
+}
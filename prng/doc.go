@@ -0,0 +1,2 @@
+//Package prng contains pseudorandom number generators
+package prng
@@ -0,0 +1,110 @@
+//This file contains jump-ahead for the Mersenne Twisters, by
+//polynomial arithmetic over GF(2)
+
+package prng
+
+import (
+	"sync"
+
+	"github.com/alanese/cryptopals/mathutil"
+)
+
+//mtWord is the type of a Mersenne Twister's state words
+type mtWord interface {
+	~uint32 | ~uint64
+}
+
+//mtRecurrence is the linear recurrence behind a Mersenne Twister:
+//each new state word is made from the words n, n-1 and n-m places back
+type mtRecurrence[T mtWord] struct {
+	n, m         int
+	upper, lower T
+	a            T
+
+	once sync.Once
+	poly mathutil.GF2Poly
+}
+
+//next returns the word following x0, x1 (the word after it) and
+//xm (m words after x0)
+func (r *mtRecurrence[T]) next(x0, x1, xm T) T {
+	y := x0&r.upper | x1&r.lower
+	v := xm ^ y>>1
+	if y&1 == 1 {
+		v ^= r.a
+	}
+	return v
+}
+
+//extend returns the n words of state followed by count more words
+//of the sequence
+func (r *mtRecurrence[T]) extend(state []T, count int) []T {
+	seq := make([]T, r.n+count)
+	copy(seq, state[:r.n])
+	for k := r.n; k < len(seq); k++ {
+		seq[k] = r.next(seq[k-r.n], seq[k-r.n+1], seq[k-r.n+r.m])
+	}
+	return seq
+}
+
+//window returns the n words of the sequence starting with the word
+//behind the next output of a twister with the given state and index
+func (r *mtRecurrence[T]) window(state []T, index int) []T {
+	return r.extend(state, index)[index : index+r.n]
+}
+
+//charPoly returns the characteristic polynomial of the recurrence,
+//of degree 19937 for both twisters. Every bit position of the
+//sequence satisfies it, so it is found by Berlekamp-Massey on the top
+//bits of any sequence, and computed only once.
+func (r *mtRecurrence[T]) charPoly() mathutil.GF2Poly {
+	r.once.Do(func() {
+		//the top bits satisfy a recurrence of degree at most the
+		//number of state bits, and twice that many determine it
+		bits := 2 * (r.n*sizeOf[T]() + 1)
+		state := make([]T, r.n)
+		for i := range state {
+			state[i] = T(1812433253*uint64(i) + 1)
+		}
+		seq := r.extend(state, bits)[r.n:]
+		s := mathutil.NewBitVector(bits)
+		for i, x := range seq {
+			if x>>(sizeOf[T]()-1) == 1 {
+				s.Set(i)
+			}
+		}
+		r.poly = mathutil.GF2MinimalPolynomial(s, bits)
+	})
+	return r.poly
+}
+
+//sizeOf returns the number of bits in a T
+func sizeOf[T mtWord]() int {
+	var x T
+	x--
+	if uint64(x) == 1<<32-1 {
+		return 32
+	}
+	return 64
+}
+
+//jump returns the window steps words further along the sequence than
+//the given window. With p the characteristic polynomial and
+//x^steps = q(x) mod p, the window steps along is the sum of the
+//windows i along for each term x^i of q, all of which are within the
+//next 19937 words.
+func (r *mtRecurrence[T]) jump(window []T, steps uint64) []T {
+	p := r.charPoly()
+	q := mathutil.GF2PolyXPowMod(steps, p)
+	seq := r.extend(window, p.Degree())
+	out := make([]T, r.n)
+	for i := 0; i <= q.Degree(); i++ {
+		if q.Coeff(i) == 0 {
+			continue
+		}
+		for j := range out {
+			out[j] ^= seq[i+j]
+		}
+	}
+	return out
+}
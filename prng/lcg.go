@@ -0,0 +1,83 @@
+//This file contains the C library and Java generators, whose seeds
+//are often taken from the clock
+
+package prng
+
+//glibcRandDegree is the number of words of state in glibc's default
+//TYPE_3 random(), and glibcRandSep the lag of its feedback
+const (
+	glibcRandDegree = 31
+	glibcRandSep    = 3
+)
+
+//GlibcRand is glibc's rand() and random(): an additive lagged
+//Fibonacci generator seeded by a Lehmer generator
+type GlibcRand struct {
+	r     [glibcRandDegree]int32
+	front int
+}
+
+//NewGlibcRand returns the generator after srand(seed). As in glibc, a
+//seed of 0 is treated as 1.
+func NewGlibcRand(seed uint32) *GlibcRand {
+	g := &GlibcRand{}
+	if seed == 0 {
+		seed = 1
+	}
+	word := int32(seed)
+	g.r[0] = word
+	for i := 1; i < glibcRandDegree; i++ {
+		//16807 * word mod 2^31 - 1, without overflow, as glibc does
+		hi, lo := word/127773, word%127773
+		word = 16807*lo - 2836*hi
+		if word < 0 {
+			word += 2147483647
+		}
+		g.r[i] = word
+	}
+	g.front = glibcRandSep
+	//glibc discards the first 310 outputs
+	for i := 0; i < 10*glibcRandDegree; i++ {
+		g.Next()
+	}
+	return g
+}
+
+//Next returns the next 31-bit output, as rand() does
+func (g *GlibcRand) Next() uint32 {
+	rear := (g.front + glibcRandDegree - glibcRandSep) % glibcRandDegree
+	g.r[g.front] += g.r[rear]
+	out := uint32(g.r[g.front]) >> 1
+	g.front = (g.front + 1) % glibcRandDegree
+	return out
+}
+
+//javaMultiplier and javaMask are the constants of java.util.Random's
+//48-bit LCG
+const (
+	javaMultiplier = 0x5DEECE66D
+	javaMask       = 1<<48 - 1
+)
+
+//JavaRandom is java.util.Random, a 48-bit LCG
+type JavaRandom struct {
+	seed uint64
+}
+
+//NewJavaRandom returns the generator made by new Random(seed), which
+//scrambles the seed
+func NewJavaRandom(seed int64) *JavaRandom {
+	return &JavaRandom{(uint64(seed) ^ javaMultiplier) & javaMask}
+}
+
+//NextBits returns the next output of the given number of bits, up
+//to 32, as Random.next
+func (j *JavaRandom) NextBits(bits int) uint32 {
+	j.seed = (j.seed*javaMultiplier + 0xB) & javaMask
+	return uint32(j.seed >> (48 - bits))
+}
+
+//NextInt returns the next 32-bit output, as Random.nextInt()
+func (j *JavaRandom) NextInt() int32 {
+	return int32(j.NextBits(32))
+}
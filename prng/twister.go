@@ -0,0 +1,232 @@
+//This file contains an implementation of a Mersenne Twister PRNG
+
+package prng
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"math/rand"
+)
+
+const twisterLength = 624
+
+//Twister is a Mersenne Twister PRNG, implementing MT19937. A
+//*Twister is a math/rand.Source64; copying a Twister clones it.
+type Twister struct {
+	x     [twisterLength]uint32
+	index int
+}
+
+var _ rand.Source64 = (*Twister)(nil)
+
+//twisterRecurrence is MT19937's recurrence, for jump-ahead
+var twisterRecurrence = &mtRecurrence[uint32]{
+	n: twisterLength, m: 397,
+	upper: 0x80000000, lower: 0x7FFFFFFF, a: 0x9908b0df,
+}
+
+//NewTwister creates a new instance of Twister with
+//the given seed.
+func NewTwister(seed uint32) Twister {
+	t := Twister{}
+	t.index = twisterLength
+	t.x[0] = seed
+	for i := 1; i < twisterLength; i++ {
+		t.x[i] = 1812433253*(t.x[i-1]^(t.x[i-1]>>30)) + uint32(i)
+	}
+	return t
+
+}
+
+//NewTwisterByArray creates a new instance of Twister seeded with an
+//array of words, as init_by_array in the reference implementation.
+//An empty key is treated as {0}, as Python does.
+func NewTwisterByArray(key []uint32) Twister {
+	if len(key) == 0 {
+		key = []uint32{0}
+	}
+	t := NewTwister(19650218)
+	i, j := 1, 0
+	for k := max(twisterLength, len(key)); k > 0; k-- {
+		t.x[i] = (t.x[i] ^ (t.x[i-1]^(t.x[i-1]>>30))*1664525) + key[j] + uint32(j)
+		i++
+		j++
+		if i >= twisterLength {
+			t.x[0] = t.x[twisterLength-1]
+			i = 1
+		}
+		if j >= len(key) {
+			j = 0
+		}
+	}
+	for k := twisterLength - 1; k > 0; k-- {
+		t.x[i] = (t.x[i] ^ (t.x[i-1]^(t.x[i-1]>>30))*1566083941) - uint32(i)
+		i++
+		if i >= twisterLength {
+			t.x[0] = t.x[twisterLength-1]
+			i = 1
+		}
+	}
+	t.x[0] = 0x80000000
+	return t
+}
+
+//NewTwisterFromState creates a new instance of Twister with
+//the given internal state, which will be twisted before the
+//next value is produced.
+func NewTwisterFromState(state [twisterLength]uint32) Twister {
+	return Twister{x: state, index: twisterLength}
+}
+
+//Next gets the next uint32 value from a Twister
+func (t *Twister) Next() uint32 {
+	if t.index >= twisterLength {
+		t.twist()
+	}
+	y := Temper(t.x[t.index])
+	t.index++
+	return y
+
+}
+
+//Temper applies MT19937's tempering to a state word, giving the
+//output. It is linear over GF(2).
+func Temper(y uint32) uint32 {
+	y ^= (y >> 11)
+	y ^= ((y << 7) & 0x9D2C5680)
+	y ^= ((y << 15) & 0xEFC60000)
+	y ^= (y >> 18)
+	return y
+}
+
+func (t *Twister) twist() {
+	var lowerMask uint32 = 0x7FFFFFFF
+	var upperMask uint32 = 0x80000000
+	for i := 0; i < twisterLength; i++ {
+		x := (t.x[i] & upperMask) | (t.x[(i+1)%twisterLength] & lowerMask)
+		xA := x >> 1
+		t.x[i] = t.x[(i+397)%twisterLength] ^ xA ^ ((x & 1) * 0x9908b0df)
+	}
+	t.index = 0
+}
+
+//Bits returns a value of k bits, for k up to 64, as Python's
+//getrandbits: the top k bits of one output if k is at most 32, and
+//otherwise the low word from the first output and the top k-32 bits
+//of the second for the high word
+func (t *Twister) Bits(k int) uint64 {
+	if k < 0 || k > 64 {
+		panic("Bits needs 0 to 64 bits")
+	}
+	if k <= 32 {
+		return uint64(t.Next()) >> (32 - k)
+	}
+	lo := uint64(t.Next())
+	return lo | uint64(t.Next())>>(64-k)<<32
+}
+
+//Uint64 returns 64 bits from two outputs, as Bits(64). It is
+//part of math/rand.Source64.
+func (t *Twister) Uint64() uint64 {
+	return t.Bits(64)
+}
+
+//Int63 returns a non-negative int64 from two outputs, as Bits(63).
+//It is part of math/rand.Source.
+func (t *Twister) Int63() int64 {
+	return int64(t.Bits(63))
+}
+
+//Seed reseeds the twister as Python's random.seed does for an
+//integer: init_by_array with the 32-bit words of |seed|, least
+//significant first. Float64 and Intn then give the same values as
+//Python's random.random and random.randrange. It is part of
+//math/rand.Source.
+func (t *Twister) Seed(seed int64) {
+	u := uint64(seed)
+	if seed < 0 {
+		u = -u
+	}
+	key := []uint32{uint32(u)}
+	if u>>32 != 0 {
+		key = append(key, uint32(u>>32))
+	}
+	*t = NewTwisterByArray(key)
+}
+
+//Float64 returns a float in [0, 1) with 53 bits of precision from
+//two outputs, as genrand_res53 in the reference implementation and
+//Python's random.random
+func (t *Twister) Float64() float64 {
+	a, b := t.Next()>>5, t.Next()>>6
+	return (float64(a)*67108864 + float64(b)) / 9007199254740992
+}
+
+//Intn returns an int in [0, n) by rejection sampling values of just
+//enough bits, as Python's random.randrange(n). Panics if n <= 0.
+func (t *Twister) Intn(n int) int {
+	if n <= 0 {
+		panic("Intn needs a positive n")
+	}
+	k := bits.Len64(uint64(n))
+	for {
+		if r := t.Bits(k); r < uint64(n) {
+			return int(r)
+		}
+	}
+}
+
+//Clone returns a copy of t, producing the same outputs
+func (t *Twister) Clone() *Twister {
+	c := *t
+	return &c
+}
+
+//State returns the internal state and the index of the next word
+//to be output from it, with index 624 meaning the state will be
+//twisted first
+func (t *Twister) State() ([twisterLength]uint32, int) {
+	return t.x, t.index
+}
+
+//SetState replaces the internal state and index, as returned by
+//State. Returns a non-nil error if index is not from 0 to 624.
+func (t *Twister) SetState(state [twisterLength]uint32, index int) error {
+	if index < 0 || index > twisterLength {
+		return errors.New("Index out of range")
+	}
+	t.x, t.index = state, index
+	return nil
+}
+
+//MarshalBinary encodes the state and index as 625 big-endian words
+func (t *Twister) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 4*(twisterLength+1))
+	for _, x := range t.x {
+		b = binary.BigEndian.AppendUint32(b, x)
+	}
+	return binary.BigEndian.AppendUint32(b, uint32(t.index)), nil
+}
+
+//UnmarshalBinary decodes a state encoded by MarshalBinary. Returns a
+//non-nil error if b is the wrong length or the index is out of range.
+func (t *Twister) UnmarshalBinary(b []byte) error {
+	if len(b) != 4*(twisterLength+1) {
+		return errors.New("Wrong length for Twister state")
+	}
+	var state [twisterLength]uint32
+	for i := range state {
+		state[i] = binary.BigEndian.Uint32(b[4*i:])
+	}
+	return t.SetState(state, int(binary.BigEndian.Uint32(b[4*twisterLength:])))
+}
+
+//Jump advances the twister by n outputs in time logarithmic in n,
+//by polynomial arithmetic modulo its characteristic polynomial. The
+//first call computes that polynomial, which takes a moment.
+func (t *Twister) Jump(n uint64) {
+	w := twisterRecurrence.jump(twisterRecurrence.window(t.x[:], t.index), n)
+	copy(t.x[:], w)
+	t.index = 0
+}
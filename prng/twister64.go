@@ -0,0 +1,195 @@
+//This file contains the 64-bit Mersenne Twister, MT19937-64
+
+package prng
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"math/rand"
+)
+
+const twister64Length = 312
+
+//Twister64 is the 64-bit Mersenne Twister, MT19937-64. A *Twister64
+//is a math/rand.Source64; copying a Twister64 clones it.
+type Twister64 struct {
+	x     [twister64Length]uint64
+	index int
+}
+
+var _ rand.Source64 = (*Twister64)(nil)
+
+//twister64Recurrence is MT19937-64's recurrence
+var twister64Recurrence = &mtRecurrence[uint64]{
+	n: twister64Length, m: 156,
+	upper: 0xFFFFFFFF80000000, lower: 0x7FFFFFFF, a: 0xB5026F5AA96619E9,
+}
+
+//NewTwister64 creates a new instance of Twister64 with the
+//given seed, as init_genrand64 in the reference implementation
+func NewTwister64(seed uint64) Twister64 {
+	t := Twister64{}
+	t.index = twister64Length
+	t.x[0] = seed
+	for i := 1; i < twister64Length; i++ {
+		t.x[i] = 6364136223846793005*(t.x[i-1]^(t.x[i-1]>>62)) + uint64(i)
+	}
+	return t
+}
+
+//NewTwister64ByArray creates a new instance of Twister64 seeded with
+//an array of words, as init_by_array64 in the reference
+//implementation. An empty key is treated as {0}.
+func NewTwister64ByArray(key []uint64) Twister64 {
+	if len(key) == 0 {
+		key = []uint64{0}
+	}
+	t := NewTwister64(19650218)
+	i, j := 1, 0
+	for k := max(twister64Length, len(key)); k > 0; k-- {
+		t.x[i] = (t.x[i] ^ (t.x[i-1]^(t.x[i-1]>>62))*3935559000370003845) + key[j] + uint64(j)
+		i++
+		j++
+		if i >= twister64Length {
+			t.x[0] = t.x[twister64Length-1]
+			i = 1
+		}
+		if j >= len(key) {
+			j = 0
+		}
+	}
+	for k := twister64Length - 1; k > 0; k-- {
+		t.x[i] = (t.x[i] ^ (t.x[i-1]^(t.x[i-1]>>62))*2862933555777941757) - uint64(i)
+		i++
+		if i >= twister64Length {
+			t.x[0] = t.x[twister64Length-1]
+			i = 1
+		}
+	}
+	t.x[0] = 1 << 63
+	return t
+}
+
+//NewTwister64FromState creates a new instance of Twister64 with
+//the given internal state, which will be twisted before the
+//next value is produced.
+func NewTwister64FromState(state [twister64Length]uint64) Twister64 {
+	return Twister64{x: state, index: twister64Length}
+}
+
+//Next gets the next uint64 value from a Twister64
+func (t *Twister64) Next() uint64 {
+	if t.index >= twister64Length {
+		t.twist()
+	}
+	y := t.x[t.index]
+	y ^= (y >> 29) & 0x5555555555555555
+	y ^= (y << 17) & 0x71D67FFFEDA60000
+	y ^= (y << 37) & 0xFFF7EEE000000000
+	y ^= (y >> 43)
+	t.index++
+	return y
+}
+
+func (t *Twister64) twist() {
+	r := twister64Recurrence
+	for i := 0; i < twister64Length; i++ {
+		t.x[i] = r.next(t.x[i], t.x[(i+1)%twister64Length], t.x[(i+r.m)%twister64Length])
+	}
+	t.index = 0
+}
+
+//Uint64 returns the next output. It is part of math/rand.Source64.
+func (t *Twister64) Uint64() uint64 {
+	return t.Next()
+}
+
+//Int63 returns the top 63 bits of the next output, as genrand64_int63
+//in the reference implementation. It is part of math/rand.Source.
+func (t *Twister64) Int63() int64 {
+	return int64(t.Next() >> 1)
+}
+
+//Seed reseeds the twister as NewTwister64(uint64(seed)), as C++'s
+//std::mt19937_64 does. It is part of math/rand.Source.
+func (t *Twister64) Seed(seed int64) {
+	*t = NewTwister64(uint64(seed))
+}
+
+//Float64 returns a float in [0, 1) from the top 53 bits of the next
+//output, as genrand64_res53 in the reference implementation
+func (t *Twister64) Float64() float64 {
+	return float64(t.Next()>>11) / 9007199254740992
+}
+
+//Intn returns an int in [0, n) by rejection sampling the top bits of
+//outputs, taking just enough bits to cover n. Panics if n <= 0.
+func (t *Twister64) Intn(n int) int {
+	if n <= 0 {
+		panic("Intn needs a positive n")
+	}
+	k := bits.Len64(uint64(n))
+	for {
+		if r := t.Next() >> (64 - k); r < uint64(n) {
+			return int(r)
+		}
+	}
+}
+
+//Clone returns a copy of t, producing the same outputs
+func (t *Twister64) Clone() *Twister64 {
+	c := *t
+	return &c
+}
+
+//State returns the internal state and the index of the next word
+//to be output from it, with index 312 meaning the state will be
+//twisted first
+func (t *Twister64) State() ([twister64Length]uint64, int) {
+	return t.x, t.index
+}
+
+//SetState replaces the internal state and index, as returned by
+//State. Returns a non-nil error if index is not from 0 to 312.
+func (t *Twister64) SetState(state [twister64Length]uint64, index int) error {
+	if index < 0 || index > twister64Length {
+		return errors.New("Index out of range")
+	}
+	t.x, t.index = state, index
+	return nil
+}
+
+//MarshalBinary encodes the state and index as 313 big-endian words
+func (t *Twister64) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 8*(twister64Length+1))
+	for _, x := range t.x {
+		b = binary.BigEndian.AppendUint64(b, x)
+	}
+	return binary.BigEndian.AppendUint64(b, uint64(t.index)), nil
+}
+
+//UnmarshalBinary decodes a state encoded by MarshalBinary. Returns a
+//non-nil error if b is the wrong length or the index is out of range.
+func (t *Twister64) UnmarshalBinary(b []byte) error {
+	if len(b) != 8*(twister64Length+1) {
+		return errors.New("Wrong length for Twister64 state")
+	}
+	var state [twister64Length]uint64
+	for i := range state {
+		state[i] = binary.BigEndian.Uint64(b[8*i:])
+	}
+	index := binary.BigEndian.Uint64(b[8*twister64Length:])
+	if index > twister64Length {
+		return errors.New("Index out of range")
+	}
+	return t.SetState(state, int(index))
+}
+
+//Jump advances the twister by n outputs in time logarithmic in n,
+//as Twister.Jump
+func (t *Twister64) Jump(n uint64) {
+	w := twister64Recurrence.jump(twister64Recurrence.window(t.x[:], t.index), n)
+	copy(t.x[:], w)
+	t.index = 0
+}
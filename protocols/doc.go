@@ -0,0 +1,3 @@
+//Package protocols contains the honest participants in
+//the key exchange and SRP protocols from set 5
+package protocols
@@ -0,0 +1,298 @@
+package protocols
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/ciphers"
+	"github.com/alanese/cryptopals/mathutil"
+	"github.com/alanese/cryptopals/pubkey"
+)
+
+//DHEchoBob implements an "echo" bot. To use:
+//Start as a goroutine, then send the chosen prime, the chosen generator, and
+//Alice's public key over in. Read Bob's public key from out.
+//Send a message encrypted with AES-CBC with the IV appended to the end over in
+//Read the echoed message, encrypted similarly, from out.
+//Close in to terminate the conversation - this will cause Bob to close out
+//and end
+func DHEchoBob(in, out chan []byte) {
+	rSource := rand.New(rand.NewSource(time.Now().UnixNano()))
+	pBytes := <-in
+	p := big.NewInt(0).SetBytes(pBytes)
+	gBytes := <-in
+	g := big.NewInt(0).SetBytes(gBytes)
+	b := pubkey.GenerateDHPrivateKey(rSource, p)
+	B := pubkey.GenerateDHPublicKey(b, p, g)
+	aBytes := <-in
+	A := big.NewInt(0)
+	A.SetBytes(aBytes)
+	out <- B.Bytes()
+	key1, _ := pubkey.NISTDiffieHellmanKeys(A, b)
+	for {
+		v, ok := <-in
+		if !ok {
+			close(out)
+			break
+		}
+		iv := v[len(v)-16:]
+		aMsgEncrypted := v[:len(v)-16]
+		aMsg := ciphers.DecryptAESCBC(aMsgEncrypted, key1, iv)
+		aMsg, _ = ciphers.StripPKCS7Padding(aMsg, 16)
+		fmt.Printf("BOB: Decrypted message from Alice: %v\n", string(aMsg))
+		bobIv := bytesutil.GenerateRandomByteSlice(16)
+		aMsg = ciphers.PKCSPad(aMsg, 16)
+		bMsgEncrypted := ciphers.EncryptAESCBC(aMsg, key1, bobIv)
+		bMsgEncrypted = append(bMsgEncrypted, bobIv...)
+		out <- bMsgEncrypted
+
+	}
+}
+
+//C35EchoBob implements an echo bot with group negotiation
+//per challenge 35
+func C35EchoBob(in, out chan []byte) {
+	p := big.NewInt(0).SetBytes(<-in)
+	g := big.NewInt(0).SetBytes(<-in)
+	out <- p.Bytes()
+	out <- g.Bytes()
+	b := pubkey.GenerateDHPrivateKey(rand.New(rand.NewSource(time.Now().UnixNano())), p)
+	B := pubkey.GenerateDHPublicKey(b, p, g)
+	A := big.NewInt(0).SetBytes(<-in)
+	out <- B.Bytes()
+	key, _ := pubkey.DiffieHellmanKeys(A, b, p)
+
+	for {
+		msg, ok := <-in
+		if !ok {
+			close(out)
+			break
+		}
+		iv := msg[len(msg)-16:]
+		encryptedMsg := msg[:len(msg)-16]
+		decrypted := ciphers.DecryptAESCBC(encryptedMsg, key, iv)
+		decrypted, _ = ciphers.StripPKCS7Padding(decrypted, 16)
+		fmt.Printf("BOB: Received message from Alice: %v\n", string(decrypted))
+
+		echoMsg := ciphers.PKCSPad(decrypted, 16)
+		newIv := bytesutil.GenerateRandomByteSlice(16)
+		encryptedEcho := ciphers.EncryptAESCBC(echoMsg, key, newIv)
+		encryptedEcho = append(encryptedEcho, newIv...)
+		out <- encryptedEcho
+	}
+
+}
+
+//SRPServer implements the server side of an SRP password
+//verification scheme
+func SRPServer(in, out chan []byte) {
+	g := big.NewInt(2)
+	k := big.NewInt(3)
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+	b := pubkey.GenerateNISTDHPrivateKey1536(rand.New(rand.NewSource(time.Now().UnixNano())))
+	salt := bytesutil.GenerateRandomByteSlice(16)
+	password := "secretpassword123"
+	salted := append(salt, []byte(password)...)
+	xH := sha256.Sum256(salted)
+	x := big.NewInt(0).SetBytes(xH[:])
+	//v := big.NewInt(0).Exp(g, x, p)
+	v := mathutil.ModExp(g, x, p)
+	//Pretend to forget x, xH
+
+	<-in //Ignore email - we're not doing multiple clients yet
+	A := big.NewInt(0).SetBytes(<-in)
+	out <- salt
+	B := big.NewInt(0).Exp(g, b, p) //g**b % p
+	tmp := big.NewInt(0).Mul(k, v)
+	B.Add(B, tmp) //kv + (g**b %p)
+	B.Mod(B, p)   //kv + g**b % p
+	out <- B.Bytes()
+
+	uH := sha256.Sum256(append(A.Bytes(), B.Bytes()...))
+	u := big.NewInt(0).SetBytes(uH[:])
+	//good to here
+	t0 := big.NewInt(0).Mul(A, big.NewInt(0).Exp(v, u, p))
+	S := big.NewInt(0).Exp(t0, b, p)
+	K := sha256.Sum256(S.Bytes())
+
+	providedHmac := <-in
+
+	hmacHasher := hmac.New(sha256.New, K[:])
+	realHmac := hmacHasher.Sum(salt)
+	if hmac.Equal(providedHmac, realHmac) {
+		out <- []byte("OK")
+	} else {
+		out <- []byte("ERROR")
+	}
+
+}
+
+//SRPClient implements the client side of an SRP password
+//verification scheme
+func SRPClient(in, out chan []byte) []byte {
+	g := big.NewInt(2)
+	k := big.NewInt(3)
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+	a := pubkey.GenerateNISTDHPrivateKey1536(rand.New(rand.NewSource(time.Now().UnixNano())))
+
+	A := big.NewInt(0).Exp(g, a, p)
+	email := "bob@example.com"
+	password := "secretpassword123"
+	out <- []byte(email)
+	out <- A.Bytes()
+	salt := <-in
+	B := big.NewInt(0).SetBytes(<-in)
+	uH := sha256.Sum256(append(A.Bytes(), B.Bytes()...))
+	u := big.NewInt(0).SetBytes(uH[:])
+	//good to here
+	salted := append(salt, []byte(password)...)
+	xH := sha256.Sum256(salted)
+	x := big.NewInt(0).SetBytes(xH[:])
+
+	t0 := big.NewInt(0).Exp(g, x, p) //g**x %p
+	t0 = t0.Mul(t0, k)
+	t1 := big.NewInt(0).Sub(B, t0)
+	t2 := big.NewInt(0).Add(a, big.NewInt(0).Mul(u, x))
+	S := big.NewInt(0).Exp(t1, t2, p)
+
+	K := sha256.Sum256(S.Bytes())
+
+	hmacHasher := hmac.New(sha256.New, K[:])
+
+	hm := hmacHasher.Sum(salt)
+
+	out <- hm
+
+	response := <-in
+	return response
+
+}
+
+//C37LogIn implements the client side of an SRP password
+//verification scheme running over a network
+func C37LogIn(username, password string) bool {
+	genbBase := "http://localhost:8080/getB?u=%v&A=%X"
+	verifyBase := "http://localhost:8080/validate?u=%v&signature=%X"
+
+	k := big.NewInt(3)
+	g := big.NewInt(2)
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+
+	rSource := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	a := pubkey.GenerateNISTDHPrivateKey1536(rSource)
+	A := pubkey.GenerateNISTDHPublicKey1536(a)
+
+	genbresp, _ := http.Get(fmt.Sprintf(genbBase, username, A))
+	respLength := genbresp.ContentLength
+	salt := make([]byte, 16)
+	genbresp.Body.Read(salt)
+	BBytes := make([]byte, respLength-16)
+	genbresp.Body.Read(BBytes)
+	B := big.NewInt(0).SetBytes(BBytes)
+
+	uH := sha256.Sum256(append(A.Bytes(), BBytes...))
+	u := big.NewInt(0).SetBytes(uH[:])
+
+	salted := append(salt, password...)
+	xH := sha256.Sum256(salted)
+	x := big.NewInt(0).SetBytes(xH[:])
+
+	t0 := big.NewInt(0).Exp(g, x, p)
+	t0.Mul(t0, k)
+	t0.Sub(B, t0)
+	t1 := big.NewInt(0).Mul(u, x)
+	t1.Add(t1, a)
+	S := big.NewInt(0).Exp(t0, t1, p)
+	K := sha256.Sum256(S.Bytes())
+
+	hasher := hmac.New(sha256.New, K[:])
+
+	verifyHmac := hasher.Sum(salt)
+
+	verifyResp, _ := http.Get(fmt.Sprintf(verifyBase, username, verifyHmac))
+	return verifyResp.StatusCode == http.StatusOK
+
+}
+
+//C38Server implements a simplified SRP server per challenge 38
+func C38Server(in, out chan []byte) {
+	password := "secret"
+	g := big.NewInt(2)
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+	salt := bytesutil.GenerateRandomByteSlice(16)
+	salted := append(salt, password...)
+	xH := sha256.Sum256(salted)
+	x := big.NewInt(0).SetBytes(xH[:])
+	v := big.NewInt(0).Exp(g, x, p)
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	b := pubkey.GenerateNISTDHPrivateKey1536(rnd)
+	B := big.NewInt(0).Exp(g, b, p)
+
+	<-in //ignore username for this simple implementation
+	A := big.NewInt(0).SetBytes(<-in)
+
+	out <- salt
+	out <- B.Bytes()
+	uH := bytesutil.GenerateRandomByteSlice(16)
+	out <- uH
+	u := big.NewInt(0).SetBytes(uH)
+
+	S := big.NewInt(0).Exp(v, u, p)
+	S.Mul(S, A)
+	S.Exp(S, b, p)
+	K := sha256.Sum256(S.Bytes())
+
+	hasher := hmac.New(sha256.New, K[:])
+	trueHmac := hasher.Sum(salt)
+
+	validateHmac := <-in
+
+	if hmac.Equal(validateHmac, trueHmac) {
+		out <- []byte("OK")
+	} else {
+		out <- []byte("ERROR")
+	}
+
+}
+
+//C38Client implements a simplified SRP client per challenge 38
+func C38Client(in, out chan []byte) bool {
+	I := "bob"
+	password := "pumbaa"
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	a := pubkey.GenerateNISTDHPrivateKey1536(rnd)
+	A := pubkey.GenerateNISTDHPublicKey1536(a)
+
+	out <- []byte(I)
+	out <- A.Bytes()
+
+	salt := <-in
+	B := big.NewInt(0).SetBytes(<-in)
+	u := big.NewInt(0).SetBytes(<-in)
+
+	salted := append(salt, password...)
+	xH := sha256.Sum256(salted)
+	x := big.NewInt(0).SetBytes(xH[:])
+	exp := big.NewInt(0).Mul(u, x)
+	exp.Add(a, exp)
+	S := big.NewInt(0).Exp(B, exp, p)
+
+	K := sha256.Sum256(S.Bytes())
+
+	hasher := hmac.New(sha256.New, K[:])
+	validateHmac := hasher.Sum(salt)
+	out <- validateHmac
+
+	resp := <-in
+
+	return string(resp) == "OK"
+
+}
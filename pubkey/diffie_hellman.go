@@ -1,12 +1,14 @@
 //This file contains various functions and constants for
 //finite-field Diffie-Hellman key exchange
 
-package main
+package pubkey
 
 import (
 	"crypto/sha256"
 	"math/big"
 	"math/rand"
+
+	"github.com/alanese/cryptopals/mathutil"
 )
 
 //NIST1536GroupSize is the order of the 1536-bit MODP group defined in RFC 3526
@@ -26,7 +28,7 @@ const NIST1536GroupSize = "ffffffffffffffffc90fdaa22168c234" +
 //GenerateDHPublicKey generates a diffie-hellman public
 //key from the given private key, prime, and generator
 func GenerateDHPublicKey(a, p, g *big.Int) *big.Int {
-	return ModExp(g, a, p)
+	return mathutil.ModExp(g, a, p)
 }
 
 //GenerateNISTDHPublicKey1536 generates a Diffie-Hellman
@@ -71,7 +73,7 @@ func GenerateNISTDHPrivateKey1536(rnd *rand.Rand) *big.Int {
 //the receiver's public key A, the sender's private key b,
 //and the chosen prime p
 func DiffieHellmanKeys(A, b, p *big.Int) ([]byte, []byte) {
-	sharedSecret := ModExp(A, b, p)
+	sharedSecret := mathutil.ModExp(A, b, p)
 	secretHash := sha256.Sum256(sharedSecret.Bytes())
 	return secretHash[:16], secretHash[16:]
 }
@@ -82,7 +84,7 @@ func DiffieHellmanKeys(A, b, p *big.Int) ([]byte, []byte) {
 func NISTDiffieHellmanKeys(A, b *big.Int) ([]byte, []byte) {
 	m := big.NewInt(0)
 	m.SetString(NIST1536GroupSize, 16)
-	sharedSecret := ModExp(A, b, m)
+	sharedSecret := mathutil.ModExp(A, b, m)
 	secretHash := sha256.Sum256(sharedSecret.Bytes())
 	return secretHash[:16], secretHash[16:]
 }
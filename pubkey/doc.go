@@ -0,0 +1,3 @@
+//Package pubkey contains RSA, DSA, and finite-field
+//Diffie-Hellman. None of it is suitable for real use.
+package pubkey
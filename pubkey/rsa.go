@@ -1,6 +1,7 @@
 //This file contains functions implementing RSA keypair generation
 //and encryption/decryption
-package main
+
+package pubkey
 
 import (
 	cr "crypto/rand"
@@ -19,7 +20,7 @@ type RSASignatureDigestInfo struct {
 	Digest          []byte
 }
 
-var sha256OID = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+var SHA256OID = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
 
 //UnmarshalDigestInfo interprets a byte slice in ASN.1 format
 //as an instance of RSASignatureDigestInfo
@@ -34,7 +35,7 @@ func UnmarshalDigestInfo(b []byte) RSASignatureDigestInfo {
 //according to the standard - see comment on RSASignatureDigestInfo
 func RSASign(msg []byte, d, n *big.Int) []byte {
 	digest := sha256.Sum256(msg)
-	digestinfo, _ := asn1.Marshal(RSASignatureDigestInfo{sha256OID, digest[:]})
+	digestinfo, _ := asn1.Marshal(RSASignatureDigestInfo{SHA256OID, digest[:]})
 
 	paddingLength := len(n.Bytes()) - len(digestinfo)
 	padding := make([]byte, paddingLength)
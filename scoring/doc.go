@@ -0,0 +1,3 @@
+//Package scoring contains functions for scoring
+//candidate plaintexts
+package scoring
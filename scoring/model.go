@@ -0,0 +1,219 @@
+//This file contains a character n-gram language model
+
+package scoring
+
+import (
+	_ "embed" //for the built-in English corpus
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Model is a character-level language model. N-gram statistics are
+//gathered over case-folded text, with the case of each letter modelled
+//separately from the byte before it, so a small sample text goes further.
+type Model struct {
+	raw   [256]int
+	uni   [256]int
+	bi    map[[2]byte]int
+	tri   map[[3]byte]int
+	cases [256][2]int //letters following each byte, lower then upper
+	total int
+}
+
+//fold lower-cases an ASCII letter, leaving other bytes alone
+func fold(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+//isLetter reports whether c is an ASCII letter
+func isLetter(c byte) bool {
+	return fold(c) >= 'a' && fold(c) <= 'z'
+}
+
+//NewModel builds a language model from a sample text
+func NewModel(corpus []byte) *Model {
+	m := &Model{
+		bi:  make(map[[2]byte]int),
+		tri: make(map[[3]byte]int),
+	}
+	folded := make([]byte, len(corpus))
+	for i, c := range corpus {
+		folded[i] = fold(c)
+	}
+	for i, c := range folded {
+		m.raw[corpus[i]]++
+		m.uni[c]++
+		if i >= 1 {
+			m.bi[[2]byte{folded[i-1], c}]++
+			if isLetter(c) {
+				upper := 0
+				if corpus[i] != c {
+					upper = 1
+				}
+				m.cases[corpus[i-1]][upper]++
+			}
+		}
+		if i >= 2 {
+			m.tri[[3]byte{folded[i-2], folded[i-1], c}]++
+		}
+	}
+	m.total = len(corpus)
+	return m
+}
+
+//ModelFromFile builds a language model from the contents of a file
+func ModelFromFile(fname string) (*Model, error) {
+	f, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	return NewModel(f), nil
+}
+
+//Counts returns the unigram counts of the model, suitable
+//for use with ScoreText
+func (m *Model) Counts() [256]int {
+	return m.raw
+}
+
+//interpolation weights for the trigram, bigram, unigram and
+//uniform estimates; unseen contexts put their weight on lower orders
+const (
+	weightTri  = 0.6
+	weightBi   = 0.3
+	weightUni  = 0.09
+	weightFlat = 0.01
+)
+
+//UnigramProb returns the probability of byte c, smoothed so
+//that no byte has probability zero
+func (m *Model) UnigramProb(c byte) float64 {
+	if m.total == 0 {
+		return 1.0 / 256
+	}
+	return (1-weightFlat)*float64(m.raw[c])/float64(m.total) + weightFlat/256
+}
+
+//foldedUnigramProb is UnigramProb over case-folded text
+func (m *Model) foldedUnigramProb(c byte) float64 {
+	if m.total == 0 {
+		return 1.0 / 256
+	}
+	return (1-weightFlat)*float64(m.uni[c])/float64(m.total) + weightFlat/256
+}
+
+//caseProb returns the probability that a letter following prev
+//has the same case as c
+func (m *Model) caseProb(prev byte, c byte) float64 {
+	upper := 0
+	if c != fold(c) {
+		upper = 1
+	}
+	counts := m.cases[prev]
+	return float64(counts[upper]+1) / float64(counts[0]+counts[1]+2)
+}
+
+//CondProb returns the probability of byte c following the given
+//context of up to two bytes, interpolating the trigram, bigram and
+//unigram estimates
+func (m *Model) CondProb(context []byte, c byte) float64 {
+	if len(context) == 0 {
+		return m.UnigramProb(c)
+	}
+	fc := fold(c)
+	p1 := m.foldedUnigramProb(fc)
+	prev := fold(context[len(context)-1])
+	var p2 float64
+	wBi := 0.0
+	if n := m.uni[prev]; n > 0 {
+		p2 = float64(m.bi[[2]byte{prev, fc}]) / float64(n)
+		wBi = weightBi
+	}
+	var p3 float64
+	wTri := 0.0
+	if len(context) >= 2 {
+		prev2 := fold(context[len(context)-2])
+		if n := m.bi[[2]byte{prev2, prev}]; n > 0 {
+			p3 = float64(m.tri[[3]byte{prev2, prev, fc}]) / float64(n)
+			wTri = weightTri
+		}
+	}
+	//renormalise over the estimates which are available
+	wUni := 1 - wBi - wTri
+	p := wTri*p3 + wBi*p2 + wUni*p1
+	if isLetter(c) {
+		p *= m.caseProb(context[len(context)-1], c)
+	}
+	return p
+}
+
+//LogLikelihood returns the natural log of the probability of text
+//under the model, using contexts of up to n-1 bytes (n from 1 to 3)
+func (m *Model) LogLikelihood(text []byte, n int) float64 {
+	ll := 0.0
+	for i, c := range text {
+		start := i - (n - 1)
+		if start < 0 {
+			start = 0
+		}
+		ll += math.Log(m.CondProb(text[start:i], c))
+	}
+	return ll
+}
+
+//go:embed english.txt
+var englishCorpus []byte
+
+var englishOnce sync.Once
+var englishModel *Model
+
+//English returns a model of English prose built from a corpus
+//included in the package, so callers need not supply one
+func English() *Model {
+	englishOnce.Do(func() {
+		englishModel = NewModel(englishCorpus)
+	})
+	return englishModel
+}
+
+var englishWordsOnce sync.Once
+var englishWords []string
+
+//EnglishWords returns up to n of the most common words in the
+//built-in English corpus, most common first, in lower case
+func EnglishWords(n int) []string {
+	englishWordsOnce.Do(func() {
+		counts := make(map[string]int)
+		word := []byte{}
+		for _, c := range append(englishCorpus, ' ') {
+			if isLetter(c) || (c == '\'' && len(word) > 0) {
+				word = append(word, fold(c))
+				continue
+			}
+			if len(word) > 0 {
+				counts[strings.TrimRight(string(word), "'")]++
+				word = word[:0]
+			}
+		}
+		for w := range counts {
+			englishWords = append(englishWords, w)
+		}
+		sort.Slice(englishWords, func(i, j int) bool {
+			a, b := englishWords[i], englishWords[j]
+			if counts[a] != counts[b] {
+				return counts[a] > counts[b]
+			}
+			return a < b
+		})
+	})
+	if n > len(englishWords) {
+		n = len(englishWords)
+	}
+	return englishWords[:n]
+}
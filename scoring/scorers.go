@@ -0,0 +1,105 @@
+//This file contains the plaintext scorers
+
+package scoring
+
+import (
+	"math"
+)
+
+//Scorer scores candidate plaintexts. Lower scores are better.
+type Scorer interface {
+	Score(ptext []byte) float64
+}
+
+//ContextScorer is implemented by scorers which may take account
+//of neighbouring bytes. Such scorers learn more from a whole text
+//than from a column of every k-th byte, so attacks which split a
+//text into columns should rescore the whole text when UsesContext
+//is true. Scorers which are not additive over bytes, such as angle
+//and chi-squared, are better left to the columns.
+type ContextScorer interface {
+	Scorer
+	UsesContext() bool
+}
+
+//ScorerFunc allows an ordinary function to be used as a Scorer
+type ScorerFunc func(ptext []byte) float64
+
+//Score calls f(ptext)
+func (f ScorerFunc) Score(ptext []byte) float64 {
+	return f(ptext)
+}
+
+//AngleScorer scores text by ScoreText, the angle between its
+//byte distribution and a target distribution
+type AngleScorer struct {
+	Dist [256]int
+}
+
+//Score returns ScoreText(ptext, s.Dist)
+func (s AngleScorer) Score(ptext []byte) float64 {
+	return ScoreText(ptext, s.Dist)
+}
+
+//ChiSquaredScorer scores text by the chi-squared statistic of its
+//byte counts against those expected under a model's unigram
+//probabilities
+type ChiSquaredScorer struct {
+	Model *Model
+}
+
+//Score returns the chi-squared statistic of ptext
+func (s ChiSquaredScorer) Score(ptext []byte) float64 {
+	if len(ptext) == 0 {
+		return 0
+	}
+	counts := FreqCount(ptext)
+	n := float64(len(ptext))
+	chi := 0.0
+	for b, obs := range counts {
+		expected := n * s.Model.UnigramProb(byte(b))
+		d := float64(obs) - expected
+		chi += d * d / expected
+	}
+	return chi
+}
+
+//NGramScorer scores text by its negative log-likelihood per byte
+//under a model, using n-grams of order N (1 to 3). Bigrams and
+//trigrams only help when the text is contiguous; on a column of
+//every k-th byte they are no better than unigrams.
+type NGramScorer struct {
+	Model *Model
+	N     int
+}
+
+//Score returns the mean negative log-likelihood of ptext
+func (s NGramScorer) Score(ptext []byte) float64 {
+	if len(ptext) == 0 {
+		return 0
+	}
+	return -s.Model.LogLikelihood(ptext, s.N) / float64(len(ptext))
+}
+
+//UsesContext reports whether s uses bigrams or trigrams
+func (s NGramScorer) UsesContext() bool {
+	return s.N > 1
+}
+
+//EnglishScorer returns a trigram scorer over the built-in
+//English model
+func EnglishScorer() Scorer {
+	return NGramScorer{Model: English(), N: 3}
+}
+
+//Best returns the index of the lowest-scoring candidate, and its score
+func Best(s Scorer, candidates [][]byte) (int, float64) {
+	best := -1
+	bestScore := math.Inf(1)
+	for i, c := range candidates {
+		if score := s.Score(c); score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, bestScore
+}
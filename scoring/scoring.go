@@ -0,0 +1,38 @@
+package scoring
+
+import (
+	"io/ioutil"
+
+	"github.com/alanese/cryptopals/mathutil"
+)
+
+//FileFreqCount counts the number of occurrences of each byte value in a file
+func FileFreqCount(fname string) ([256]int, error) {
+	f, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return [256]int{}, err
+	}
+	return FreqCount(f), nil
+}
+
+//FreqCount counts the number of occurrences of each byte value in a slice
+func FreqCount(sample []byte) [256]int {
+	var counts [256]int
+	for _, v := range sample {
+		counts[v]++
+	}
+	return counts
+}
+
+//ScoreText computes a score for a potential plaintext.
+//The score is the angle between the vector for the plaintext's
+//character distribution and the vector for the target distribution.
+//Lower scores are better.
+func ScoreText(ptext []byte, targetDist [256]int) float64 {
+	textDist := FreqCount(ptext)
+	score, err := mathutil.VectorAngle(textDist[:], targetDist[:])
+	if err != nil {
+		panic("WHAT HAPPEN") // this should never happen
+	}
+	return score
+}
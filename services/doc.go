@@ -0,0 +1,6 @@
+//Package services contains the vulnerable network-facing services
+//from the challenges as HTTP handlers. Each service registers its
+//endpoints on a ServeMux, so a server can enable any combination
+//of them. Checks that pass return 200 OK; checks that fail return
+//a 500 error, which is what the HTTP oracles in package oracles expect.
+package services
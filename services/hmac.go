@@ -0,0 +1,46 @@
+//This file contains the HMAC timing leak service from
+//challenges 31 and 32
+
+package services
+
+import (
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/hashes"
+)
+
+//HMACService verifies HMAC-SHA1 signatures of file names with
+//an insecure early-exit comparison which sleeps for ByteDelay
+//after each matching byte
+type HMACService struct {
+	Secret    []byte
+	ByteDelay time.Duration
+}
+
+//Register adds the service's endpoint to mux:
+///hmac?file=...&signature=<hex>
+func (s *HMACService) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/hmac", s.verify)
+}
+
+//verify checks the signature query parameter against the
+//HMAC of the file query parameter
+func (s *HMACService) verify(rw http.ResponseWriter, rq *http.Request) {
+	file := []byte(rq.URL.Query().Get("file"))
+	sig, _ := hex.DecodeString(rq.URL.Query().Get("signature"))
+	sig = bytesutil.PadLeft(sig, 0, 20)
+	v := hashes.MACVerifier{
+		MAC:       hashes.HMAC{New: hashes.SHA1.NewHash, Key: s.Secret},
+		Compare:   hashes.EarlyExit,
+		ByteDelay: s.ByteDelay,
+	}
+	if v.Verify(file, sig) {
+		rw.Write([]byte("OK"))
+	} else {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Invalid hash"))
+	}
+}
@@ -0,0 +1,53 @@
+//This file contains the CBC padding oracle service from
+//challenge 17
+
+package services
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//PaddingService is the CBC padding oracle from challenge 17,
+//reporting whether a ciphertext decrypts under Key to a properly
+//padded plaintext
+type PaddingService struct {
+	Key []byte
+}
+
+//Register adds the service's endpoints to mux:
+///padding?iv=<hex>&ctext=<hex> is the oracle, and /padding/sample
+//returns a fresh challenge ciphertext as the hex IV and ciphertext
+//on separate lines
+func (s *PaddingService) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/padding", s.check)
+	mux.HandleFunc("/padding/sample", s.sample)
+}
+
+//check decrypts the ctext query parameter and checks its padding
+func (s *PaddingService) check(w http.ResponseWriter, rq *http.Request) {
+	iv, err1 := hex.DecodeString(rq.URL.Query().Get("iv"))
+	ctext, err2 := hex.DecodeString(rq.URL.Query().Get("ctext"))
+	if err1 != nil || err2 != nil || len(iv) != len(s.Key) || len(ctext)%len(s.Key) != 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Malformed request"))
+		return
+	}
+	if oracles.Challenge17Decrypt(ctext, s.Key, iv) != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Invalid padding"))
+		return
+	}
+	w.Write([]byte("OK"))
+}
+
+//sample encrypts one of the challenge 17 strings under a random IV
+func (s *PaddingService) sample(w http.ResponseWriter, rq *http.Request) {
+	iv := bytesutil.GenerateRandomByteSlice(len(s.Key))
+	ctext := oracles.Challenge17Encrypt(s.Key, iv)
+	fmt.Fprintf(w, "%x\n%x\n", iv, ctext)
+}
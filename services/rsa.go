@@ -0,0 +1,51 @@
+//This file contains the RSA PKCS#1v1.5 padding oracle service
+//from challenges 47 and 48
+
+package services
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/alanese/cryptopals/oracles"
+)
+
+//RSAService is the RSA padding oracle from challenges 47 and 48,
+//reporting whether a ciphertext decrypts under the private key
+//[D, N] to a PKCS#1v1.5-conformant plaintext
+type RSAService struct {
+	E *big.Int
+	D *big.Int
+	N *big.Int
+}
+
+//Register adds the service's endpoints to mux:
+///rsa?ctext=<hex> is the oracle, and /rsa/key returns the hex
+//public exponent and modulus on separate lines
+func (s *RSAService) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/rsa", s.check)
+	mux.HandleFunc("/rsa/key", s.key)
+}
+
+//check decrypts the ctext query parameter and checks its padding
+func (s *RSAService) check(w http.ResponseWriter, rq *http.Request) {
+	ctext, err := hex.DecodeString(rq.URL.Query().Get("ctext"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Malformed ciphertext"))
+		return
+	}
+	if !oracles.C47PaddingOracle(ctext, s.D, s.N) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Invalid padding"))
+		return
+	}
+	w.Write([]byte("OK"))
+}
+
+//key returns the public key
+func (s *RSAService) key(w http.ResponseWriter, rq *http.Request) {
+	fmt.Fprintf(w, "%x\n%x\n", s.E, s.N)
+}
@@ -0,0 +1,135 @@
+//This file contains the SRP login service from challenge 37
+
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alanese/cryptopals/bytesutil"
+	"github.com/alanese/cryptopals/pubkey"
+)
+
+//srpUser holds the data necessary for the SRP password
+//verification scheme per challenge 37
+type srpUser struct {
+	password string
+	salt     []byte
+	v        *big.Int
+	A        *big.Int
+	B        *big.Int
+	u        *big.Int
+}
+
+//SRPService is the server side of the SRP password verification
+//scheme from challenge 37. It does not reject A = 0 (mod N), so
+//logins can be forged without the password.
+type SRPService struct {
+	mu    sync.Mutex
+	users map[string]*srpUser
+	k     *big.Int
+	g     *big.Int
+	p     *big.Int
+	b     *big.Int
+}
+
+//NewSRPService creates an SRP service for the given users,
+//a map from username to password
+func NewSRPService(users map[string]string) *SRPService {
+	p, _ := big.NewInt(0).SetString(pubkey.NIST1536GroupSize, 16)
+	s := &SRPService{
+		users: make(map[string]*srpUser),
+		k:     big.NewInt(3),
+		g:     big.NewInt(2),
+		p:     p,
+		b:     pubkey.GenerateNISTDHPrivateKey1536(rand.New(rand.NewSource(time.Now().UnixNano()))),
+	}
+	for name, password := range users {
+		s.users[name] = &srpUser{password: password}
+	}
+	return s
+}
+
+//Register adds the service's endpoints to mux:
+///getB?u=...&A=<hex> and /validate?u=...&signature=<hex>
+func (s *SRPService) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/getB", s.generateB)
+	mux.HandleFunc("/validate", s.verifyHash)
+}
+
+//generateB implements the first C->S->C exchange of an
+//SRP password verification scheme
+func (s *SRPService) generateB(w http.ResponseWriter, rq *http.Request) {
+	A, ok := big.NewInt(0).SetString(rq.URL.Query().Get("A"), 16)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Malformed A"))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.users[rq.URL.Query().Get("u")]
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Error"))
+		return
+	}
+	d.A = A
+	if d.salt == nil {
+		d.salt = bytesutil.GenerateRandomByteSlice(16)
+		salted := append(append([]byte{}, d.salt...), d.password...)
+		xH := sha256.Sum256(salted)
+		x := big.NewInt(0).SetBytes(xH[:])
+		d.v = x.Exp(s.g, x, s.p)
+	}
+
+	B := big.NewInt(0).Exp(s.g, s.b, s.p)
+	t0 := big.NewInt(0).Mul(s.k, d.v)
+	B.Add(B, t0)
+	B.Mod(B, s.p)
+	d.B = B
+	w.Write(append(append([]byte{}, d.salt...), B.Bytes()...))
+	uH := sha256.Sum256(append(A.Bytes(), B.Bytes()...))
+	d.u = big.NewInt(0).SetBytes(uH[:])
+}
+
+//verifyHash implements the verification component of an SRP
+//password verification scheme
+func (s *SRPService) verifyHash(w http.ResponseWriter, rq *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.users[rq.URL.Query().Get("u")]
+	if !ok || d.u == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Error"))
+		return
+	}
+
+	S := big.NewInt(0).Exp(d.v, d.u, s.p)
+	S.Mul(S, d.A)
+	S.Exp(S, s.b, s.p)
+	K := sha256.Sum256(S.Bytes())
+
+	hasher := hmac.New(sha256.New, K[:])
+
+	trueHmac := hasher.Sum(d.salt)
+	signature, err := hex.DecodeString(rq.URL.Query().Get("signature"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Malformed signature"))
+		return
+	}
+
+	if hmac.Equal(trueHmac, signature) {
+		w.Write([]byte("OK"))
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Error"))
+	}
+}